@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 
 	"live-shopping-ai/backend/internal/domain/services"
 	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/infrastructure/auth"
 	"live-shopping-ai/backend/internal/infrastructure/database"
 	"live-shopping-ai/backend/internal/infrastructure/mlclient"
+	"live-shopping-ai/backend/internal/infrastructure/payment"
 	"live-shopping-ai/backend/internal/infrastructure/storage"
 	"live-shopping-ai/backend/internal/infrastructure/webrtc"
 	"live-shopping-ai/backend/internal/routes"
@@ -20,37 +25,99 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	db := database.InitDatabase()
+	defer db.Close()
 
 	productRepo := database.NewPostgresProductRepository(db)
 	pinnedRepo := database.NewPostgresPinnedRepository(db)
 	liveStreamRepo := database.NewPostgresLiveStreamRepository(db)
-	mlRepo := mlclient.NewHttpMLRepository()
+	userRepo := database.NewPostgresUserRepository(db)
+	sellerRepo := database.NewPostgresSellerRepository(db)
+	refreshTokenRepo := database.NewPostgresRefreshTokenRepository(db)
+	cartRepo := database.NewPostgresCartRepository(db)
+	orderRepo := database.NewPostgresOrderRepository(db)
+	paymentRepo := database.NewPostgresPaymentRepository(db)
+	stockRepo := database.NewPostgresStockRepository(db)
+	variantRepo := database.NewPostgresVariantRepository(db)
+	autoPinRepo := database.NewPostgresAutoPinRepository(db)
+	detectionRepo := database.NewPostgresDetectionRepository(db)
+	trainingJobRepo := database.NewPostgresTrainingJobRepository(db)
+	catalogueRepo := database.NewPostgresCatalogueRepository(db)
+	detectionSettingsRepo := database.NewPostgresDetectionSettingsRepository(db)
+	mlRepo := mlclient.NewBatchingMLRepository(mlclient.NewHttpMLRepository())
 	storageRepo := storage.NewStorageService()
 	webrtcRepo := webrtc.NewMemoryWebRTCRepository()
-
-	productService := services.NewProductService(productRepo, pinnedRepo, mlRepo, storageRepo)
-	webrtcService := services.NewWebRTCService(webrtcRepo, liveStreamRepo)
-	streamService := services.NewStreamService(mlRepo, pinnedRepo)
-	liveStreamService := services.NewLiveStreamService(liveStreamRepo)
-
-	productHandler := handlers.NewProductHandler(productService)
-	webrtcHandler := handlers.NewWebRTCHandler(webrtcService)
+	tokenRepo := auth.NewJWTTokenRepository()
+	paymentProvider := payment.NewFakePaymentProvider()
+
+	trainingJobService := services.NewTrainingJobService(trainingJobRepo, catalogueRepo, mlRepo, webrtcRepo)
+	retrainService := services.NewRetrainService(catalogueRepo, trainingJobService)
+	detectionSettingsService := services.NewDetectionSettingsService(detectionSettingsRepo)
+	productService := services.NewProductService(productRepo, variantRepo, pinnedRepo, mlRepo, storageRepo, trainingJobService, retrainService, detectionSettingsService)
+	autoPinService := services.NewAutoPinService(autoPinRepo, productRepo, pinnedRepo, webrtcRepo)
+	streamService := services.NewStreamService(mlRepo, pinnedRepo, liveStreamRepo, detectionRepo, autoPinService, detectionSettingsService)
+	webrtcService := services.NewWebRTCService(webrtcRepo, liveStreamRepo, streamService)
+	liveStreamService := services.NewLiveStreamService(liveStreamRepo, sellerRepo, detectionRepo, productRepo, retrainService)
+	authService := services.NewAuthService(userRepo, sellerRepo, refreshTokenRepo, tokenRepo)
+	inventoryService := services.NewInventoryService(stockRepo, productRepo, variantRepo, webrtcRepo)
+	cartService := services.NewCartService(cartRepo, productRepo, variantRepo, pinnedRepo, liveStreamRepo, inventoryService)
+	orderService := services.NewOrderService(orderRepo, paymentRepo, inventoryService)
+	variantService := services.NewVariantService(variantRepo, productRepo, storageRepo, inventoryService, retrainService)
+	searchService := services.NewSearchService(mlRepo, productRepo, liveStreamRepo, detectionSettingsService)
+	paymentService := services.NewPaymentService(paymentRepo, orderRepo, inventoryService, paymentProvider)
+
+	go inventoryService.RunReservationSweeper(context.Background())
+	trainingJobService.ResumeJobs(context.Background())
+	go retrainService.RunScheduler(context.Background())
+
+	productHandler := handlers.NewProductHandler(productService, inventoryService)
+	variantHandler := handlers.NewVariantHandler(variantService, inventoryService)
+	webrtcHandler := handlers.NewWebRTCHandler(webrtcService, authService)
 	streamHandler := handlers.NewStreamHandler(streamService)
 	liveStreamHandler := handlers.NewLiveStreamHandler(liveStreamService)
-
-	router := setupRouter(productHandler, webrtcHandler, streamHandler, liveStreamHandler)
+	authHandler := handlers.NewAuthHandler(authService)
+	cartHandler := handlers.NewCartHandler(cartService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	autoPinHandler := handlers.NewAutoPinHandler(autoPinService)
+	trainingJobHandler := handlers.NewTrainingJobHandler(trainingJobService)
+	retrainHandler := handlers.NewRetrainHandler(retrainService)
+	detectionSettingsHandler := handlers.NewDetectionSettingsHandler(detectionSettingsService)
+
+	router := setupRouter(authService, productHandler, variantHandler, webrtcHandler, streamHandler, liveStreamHandler, authHandler, cartHandler, orderHandler, paymentHandler, searchHandler, autoPinHandler, trainingJobHandler, retrainHandler, detectionSettingsHandler)
 
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
 
 func setupRouter(
+	authService services.AuthService,
 	productHandler *handlers.ProductHandler,
+	variantHandler *handlers.VariantHandler,
 	webrtcHandler *handlers.WebRTCHandler,
 	streamHandler *handlers.StreamHandler,
 	liveStreamHandler *handlers.LiveStreamHandler,
+	authHandler *handlers.AuthHandler,
+	cartHandler *handlers.CartHandler,
+	orderHandler *handlers.OrderHandler,
+	paymentHandler *handlers.PaymentHandler,
+	searchHandler *handlers.SearchHandler,
+	autoPinHandler *handlers.AutoPinHandler,
+	trainingJobHandler *handlers.TrainingJobHandler,
+	retrainHandler *handlers.RetrainHandler,
+	detectionSettingsHandler *handlers.DetectionSettingsHandler,
 ) *gin.Engine {
 	r := gin.Default()
+	// Without trusted proxies ClientIP ignores X-Forwarded-For, so clients
+	// cannot pick the address they are rate limited under.
+	if err := r.SetTrustedProxies(parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -65,10 +132,20 @@ func setupRouter(
 		c.Next()
 	})
 
-	routes.RegisterProductRoutes(r, productHandler)
+	routes.RegisterProductRoutes(r, productHandler, authService)
+	routes.RegisterVariantRoutes(r, variantHandler, authService)
 	routes.RegisterWebRTCRoutes(r, webrtcHandler)
-	routes.RegisterStreamRoutes(r, streamHandler)
-	routes.SetupLiveStreamRoutes(r, liveStreamHandler)
+	routes.RegisterStreamRoutes(r, streamHandler, authService)
+	routes.SetupLiveStreamRoutes(r, liveStreamHandler, authService)
+	routes.RegisterAuthRoutes(r, authHandler, authService)
+	routes.RegisterCartRoutes(r, cartHandler, authService)
+	routes.RegisterOrderRoutes(r, orderHandler, authService)
+	routes.RegisterPaymentRoutes(r, paymentHandler, authService)
+	routes.RegisterSearchRoutes(r, searchHandler, authService)
+	routes.RegisterAutoPinRoutes(r, autoPinHandler, authService)
+	routes.RegisterTrainingJobRoutes(r, trainingJobHandler, authService)
+	routes.RegisterRetrainRoutes(r, retrainHandler, authService)
+	routes.RegisterDetectionSettingsRoutes(r, detectionSettingsHandler, authService)
 
 	r.Static("/uploads", "./uploads")
 
@@ -76,5 +153,24 @@ func setupRouter(
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 
+	r.GET("/ready", func(c *gin.Context) {
+		if err := database.Ready(c.Request.Context()); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	})
+
 	return r
-}
\ No newline at end of file
+}
+// parseTrustedProxies splits a comma-separated list of proxy addresses or
+// CIDRs. An empty list trusts no proxy.
+func parseTrustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"live-shopping-ai/backend/internal/infrastructure/database"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [n]    roll back the last n migrations (default 1)
+  status      list migrations and whether they are applied`
+
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+
+	db := database.Connect()
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatal("Migration load error:", err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied  %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("invalid step count %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+}
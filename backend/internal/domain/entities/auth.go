@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RolePublisher = "publisher"
+	RoleViewer    = "viewer"
+	// RoleSampler is the server's own participant pulling the publisher's
+	// video for product detection. Clients cannot join with it.
+	RoleSampler = "sampler"
+	// RoleDashboard is a seller watching their own room from the admin
+	// dashboard for notifications such as training progress. It neither
+	// publishes nor counts as a participant.
+	RoleDashboard = "dashboard"
+)
+
+// StreamClaims are carried by the signed token a client presents when opening
+// a signaling WebSocket.
+type StreamClaims struct {
+	Subject   string    `json:"sub"`
+	Role      string    `json:"role"`
+	SellerID  int       `json:"seller_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type StreamTokenRequest struct {
+	Role string `json:"role" binding:"required,oneof=publisher dashboard viewer"`
+}
+
+type StreamTokenResponse struct {
+	Token     string    `json:"token"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+const sellerRoomPrefix = "seller-"
+
+// SellerRoomID returns the signaling room owned by a seller.
+func SellerRoomID(sellerID int) string {
+	return sellerRoomPrefix + strconv.Itoa(sellerID)
+}
+
+// SellerIDFromRoom is the inverse of SellerRoomID.
+func SellerIDFromRoom(roomID string) (int, bool) {
+	if !strings.HasPrefix(roomID, sellerRoomPrefix) {
+		return 0, false
+	}
+	sellerID, err := strconv.Atoi(strings.TrimPrefix(roomID, sellerRoomPrefix))
+	if err != nil || sellerID <= 0 {
+		return 0, false
+	}
+	return sellerID, true
+}
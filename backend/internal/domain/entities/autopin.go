@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// Auto-pin modes. In suggest mode a detected product is offered to the
+// seller with a pin_suggestion message instead of being pinned.
+const (
+	AutoPinModeOff     = "off"
+	AutoPinModeSuggest = "suggest"
+	AutoPinModeAuto    = "auto"
+)
+
+// AutoPinSettings is a seller's policy for pinning products detected in the
+// live stream.
+type AutoPinSettings struct {
+	SellerID int    `json:"seller_id"`
+	Mode     string `json:"mode"`
+	// MinSimilarity is the similarity a prediction needs to count.
+	MinSimilarity float64 `json:"min_similarity"`
+	// MinConsecutiveFrames is how many frames in a row must agree on the
+	// same product before it is acted on.
+	MinConsecutiveFrames int `json:"min_consecutive_frames"`
+	// CooldownSeconds is how long a product is left alone after it was
+	// pinned or suggested.
+	CooldownSeconds int `json:"cooldown_seconds"`
+	// MaxPinned is how many products auto-pinning keeps pinned at once; the
+	// oldest pins make way for new ones.
+	MaxPinned int       `json:"max_pinned"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultAutoPinSettings is the policy of sellers who have not set their own.
+func DefaultAutoPinSettings(sellerID int) *AutoPinSettings {
+	return &AutoPinSettings{
+		SellerID:             sellerID,
+		Mode:                 AutoPinModeAuto,
+		MinSimilarity:        0.8,
+		MinConsecutiveFrames: 3,
+		CooldownSeconds:      30,
+		MaxPinned:            1,
+	}
+}
+
+func (s *AutoPinSettings) Cooldown() time.Duration {
+	return time.Duration(s.CooldownSeconds) * time.Second
+}
+
+type AutoPinSettingsRequest struct {
+	Mode                 string  `json:"mode" binding:"required,oneof=off suggest auto"`
+	MinSimilarity        float64 `json:"min_similarity" binding:"min=0,max=1"`
+	MinConsecutiveFrames int     `json:"min_consecutive_frames" binding:"min=1,max=30"`
+	CooldownSeconds      int     `json:"cooldown_seconds" binding:"min=0,max=3600"`
+	MaxPinned            int     `json:"max_pinned" binding:"min=1,max=10"`
+}
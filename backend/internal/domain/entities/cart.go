@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+type Cart struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Items     []CartItem `json:"items"`
+	Total     float64    `json:"total"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CartItem is a product added to a cart from a live room. LiveStreamID
+// records the stream it was added from so the eventual order can be
+// attributed to it.
+type CartItem struct {
+	ID           int  `json:"id"`
+	CartID       int  `json:"cart_id"`
+	ProductID    int  `json:"product_id"`
+	VariantID    *int `json:"variant_id,omitempty"`
+	LiveStreamID int  `json:"livestream_id"`
+	Quantity     int  `json:"quantity"`
+	// ReservedUntil is when the stock held for this item is released if
+	// the cart is not checked out; nil once the reservation has expired.
+	ReservedUntil *time.Time      `json:"reserved_until,omitempty"`
+	AddedAt       time.Time       `json:"added_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Product       *Product        `json:"product,omitempty"`
+	Variant       *ProductVariant `json:"variant,omitempty"`
+}
+
+// UnitPrice is the variant's price when the item is a variant and the
+// product's price otherwise.
+func (i *CartItem) UnitPrice() float64 {
+	if i.Variant != nil {
+		return i.Variant.Price
+	}
+	if i.Product != nil {
+		return i.Product.Price
+	}
+	return 0
+}
+
+type AddCartItemRequest struct {
+	ProductID    int  `json:"product_id" binding:"required"`
+	VariantID    *int `json:"variant_id"`
+	LiveStreamID int  `json:"livestream_id" binding:"required"`
+	Quantity     int  `json:"quantity" binding:"omitempty,min=1"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
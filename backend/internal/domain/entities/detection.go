@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// Detection is a product recognised in one sampled frame of a live stream.
+type Detection struct {
+	ID           int64     `json:"id"`
+	LiveStreamID int       `json:"livestream_id"`
+	ProductID    int       `json:"product_id"`
+	DetectedAt   time.Time `json:"detected_at"`
+	BBox         [4]int    `json:"bbox"`
+	Similarity   float64   `json:"similarity"`
+	Confidence   float64   `json:"confidence"`
+}
+
+// TimelineParams is bound from the query string of the timeline endpoint.
+type TimelineParams struct {
+	// Gap is how many seconds a product may be missing from the frames
+	// before its appearance is split in two. Defaults to
+	// DefaultTimelineGapSeconds.
+	Gap       int  `form:"gap" binding:"omitempty,min=1,max=600"`
+	ProductID *int `form:"product_id"`
+}
+
+// DefaultTimelineGapSeconds covers a few missed samples at the default
+// sampling rate.
+const DefaultTimelineGapSeconds = 10
+
+// ProductAppearance is one stretch of a stream during which a product was
+// on camera. Offsets are seconds from the start of the stream.
+type ProductAppearance struct {
+	ProductID     int       `json:"product_id"`
+	StartedAt     time.Time `json:"started_at"`
+	EndedAt       time.Time `json:"ended_at"`
+	StartOffset   float64   `json:"start_offset"`
+	EndOffset     float64   `json:"end_offset"`
+	Detections    int       `json:"detections"`
+	MaxSimilarity float64   `json:"max_similarity"`
+}
+
+type ProductTimeline struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	// OnCameraSeconds is the total length of the product's appearances.
+	OnCameraSeconds float64             `json:"on_camera_seconds"`
+	Appearances     []ProductAppearance `json:"appearances"`
+}
+
+type LiveStreamTimeline struct {
+	LiveStreamID int               `json:"livestream_id"`
+	StartedAt    time.Time         `json:"started_at"`
+	EndedAt      *time.Time        `json:"ended_at,omitempty"`
+	Products     []ProductTimeline `json:"products"`
+}
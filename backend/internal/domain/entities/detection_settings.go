@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// DetectionSettings tunes how the ML service finds a seller's products in
+// frames. They are sent along with every prediction for the seller.
+type DetectionSettings struct {
+	SellerID int `json:"seller_id"`
+	// SimilarityThreshold is how close a detected object has to be to a
+	// catalogue image to be recognised as that product.
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+	// MinConfidence is the YOLO confidence below which objects are not
+	// considered at all.
+	MinConfidence float64 `json:"min_confidence"`
+	// IgnoredClasses are YOLO class names never matched against the
+	// catalogue, such as the seller in front of the camera.
+	IgnoredClasses []string `json:"ignored_classes"`
+	// ROI, when set, limits detection to objects centred inside it.
+	ROI       *RegionOfInterest `json:"roi"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// RegionOfInterest is a rectangle in fractions of the frame's width and
+// height, so it holds whatever resolution frames are sent at.
+type RegionOfInterest struct {
+	X      float64 `json:"x" binding:"min=0,max=1"`
+	Y      float64 `json:"y" binding:"min=0,max=1"`
+	Width  float64 `json:"width" binding:"gt=0,max=1"`
+	Height float64 `json:"height" binding:"gt=0,max=1"`
+}
+
+// DefaultDetectionSettings are the settings of sellers who have not set
+// their own, and match the ML service's own defaults.
+func DefaultDetectionSettings(sellerID int) *DetectionSettings {
+	return &DetectionSettings{
+		SellerID:            sellerID,
+		SimilarityThreshold: 0.7,
+		MinConfidence:       0.4,
+		IgnoredClasses:      []string{"person"},
+	}
+}
+
+type DetectionSettingsRequest struct {
+	SimilarityThreshold float64           `json:"similarity_threshold" binding:"min=0,max=1"`
+	MinConfidence       float64           `json:"min_confidence" binding:"min=0,max=1"`
+	IgnoredClasses      []string          `json:"ignored_classes" binding:"max=80,dive,required,max=64"`
+	ROI                 *RegionOfInterest `json:"roi"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "errors"
+
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrForbidden          = errors.New("forbidden")
+	ErrConflict           = errors.New("conflict")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	// ErrMLUnavailable means the ML service could not be reached or is
+	// failing fast behind its circuit breaker.
+	ErrMLUnavailable = errors.New("ML service unavailable")
+)
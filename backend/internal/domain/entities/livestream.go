@@ -4,7 +4,7 @@ import "time"
 
 type LiveStream struct {
 	ID          int       `json:"id" db:"id"`
-	SellerID    string    `json:"seller_id" db:"seller_id"`
+	SellerID    int       `json:"seller_id" db:"seller_id"`
 	SellerName  string    `json:"seller_name" db:"seller_name"`
 	Title       string    `json:"title" db:"title"`
 	Description string    `json:"description" db:"description"`
@@ -17,8 +17,8 @@ type LiveStream struct {
 }
 
 type LiveStreamRequest struct {
-	SellerID    string `json:"seller_id" binding:"required"`
-	SellerName  string `json:"seller_name" binding:"required"`
+	SellerID    int    `json:"-"`
+	SellerName  string `json:"seller_name"`
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
 }
@@ -30,7 +30,9 @@ type LiveStreamResponse struct {
 }
 
 type LiveStreamListResponse struct {
-	Success bool         `json:"success"`
-	Data    []LiveStream `json:"data"`
-	Message string       `json:"message,omitempty"`
+	Success    bool         `json:"success"`
+	Data       []LiveStream `json:"data"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      int          `json:"total"`
+	Message    string       `json:"message,omitempty"`
 }
\ No newline at end of file
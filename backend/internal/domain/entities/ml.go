@@ -24,4 +24,19 @@ type TrainingResponse struct {
 	TotalEmbeddings int    `json:"total_embeddings"`
 	UniqueProducts  int    `json:"unique_products"`
 	Status          string `json:"status"`
-}
\ No newline at end of file
+	FineTune        bool   `json:"fine_tune"`
+}
+
+// MaxBatchFrames caps how many frames one batch prediction may carry.
+const MaxBatchFrames = 16
+
+// FramePrediction is the outcome for one frame of a batch prediction: what
+// was found in it, or why it could not be predicted.
+type FramePrediction struct {
+	*PredictionResponse
+	Error string `json:"error,omitempty"`
+}
+
+type BatchPredictionResponse struct {
+	Results []FramePrediction `json:"results"`
+}
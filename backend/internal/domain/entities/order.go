@@ -0,0 +1,113 @@
+package entities
+
+import "time"
+
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusPacked    = "packed"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// orderTransitions lists the statuses each status may move to. Cancelled and
+// refunded are terminal; once money has been taken an order can only be
+// refunded, not cancelled.
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusPacked, OrderStatusRefunded},
+	OrderStatusPacked:    {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered, OrderStatusRefunded},
+	OrderStatusDelivered: {OrderStatusRefunded},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+}
+
+// sellerOrderStatuses are the statuses a seller may move an order to by hand.
+// Paid is only reached through a payment webhook and refunded only through a
+// refund with the payment provider.
+var sellerOrderStatuses = map[string]bool{
+	OrderStatusPacked:    true,
+	OrderStatusShipped:   true,
+	OrderStatusDelivered: true,
+}
+
+// IsSellerOrderStatus reports whether a seller may move an order to status
+// directly.
+func IsSellerOrderStatus(status string) bool {
+	return sellerOrderStatuses[status]
+}
+
+// OrderTransitionRestocks reports whether moving an order between the two
+// statuses returns its items to stock, i.e. the goods never left the seller.
+func OrderTransitionRestocks(from, to string) bool {
+	switch to {
+	case OrderStatusCancelled:
+		return true
+	case OrderStatusRefunded:
+		return from == OrderStatusPaid || from == OrderStatusPacked
+	}
+	return false
+}
+
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransitionOrder reports whether an order may move from one status to
+// another.
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Order struct {
+	ID           int          `json:"id"`
+	UserID       int          `json:"user_id"`
+	SellerID     int          `json:"seller_id"`
+	LiveStreamID *int         `json:"livestream_id,omitempty"`
+	Status       string       `json:"status"`
+	TotalAmount  float64      `json:"total_amount"`
+	Items        []OrderItem  `json:"items"`
+	Events       []OrderEvent `json:"events,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// OrderItem snapshots the product name and price at checkout so later
+// catalogue edits do not change what the buyer paid for.
+type OrderItem struct {
+	ID          int     `json:"id"`
+	OrderID     int     `json:"order_id"`
+	ProductID   *int    `json:"product_id,omitempty"`
+	VariantID   *int    `json:"variant_id,omitempty"`
+	ProductName string  `json:"product_name"`
+	VariantName string  `json:"variant_name,omitempty"`
+	UnitPrice   float64 `json:"unit_price"`
+	Quantity    int     `json:"quantity"`
+}
+
+// OrderEvent is one entry in an order's status history. FromStatus is empty
+// for the event recording the order's creation and ActorUserID is nil for
+// transitions made by the system.
+type OrderEvent struct {
+	ID          int       `json:"id"`
+	OrderID     int       `json:"order_id"`
+	FromStatus  string    `json:"from_status,omitempty"`
+	ToStatus    string    `json:"to_status"`
+	ActorUserID *int      `json:"actor_user_id,omitempty"`
+	Note        string    `json:"note,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type OrderTransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageCursor marks where a keyset-paginated listing left off: the sort it
+// was taken under plus the sort value and ID of the last row returned.
+type PageCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// Encode renders the cursor as the opaque next_cursor token handed to
+// clients.
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor parses a next_cursor token and checks it was issued for
+// the given sort.
+func DecodePageCursor(token string, sort string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidInput, cursor.Sort)
+	}
+	return &cursor, nil
+}
+
+// PageLimit clamps a requested page size to [1, MaxPageLimit], using
+// DefaultPageLimit when none was given.
+func PageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+const (
+	ProductSortNewest    = "newest"
+	ProductSortOldest    = "oldest"
+	ProductSortPriceAsc  = "price_asc"
+	ProductSortPriceDesc = "price_desc"
+	ProductSortName      = "name"
+)
+
+// ProductFilter selects a page of products. Bound from the query string of
+// the product listing endpoints.
+type ProductFilter struct {
+	SellerID     *int       `form:"seller_id"`
+	MinPrice     *float64   `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice     *float64   `form:"max_price" binding:"omitempty,min=0"`
+	CreatedAfter *time.Time `form:"created_after"`
+	HasImages    *bool      `form:"has_images"`
+	Sort         string     `form:"sort" binding:"omitempty,oneof=newest oldest price_asc price_desc name"`
+	Limit        int        `form:"limit" binding:"omitempty,min=1"`
+	Cursor       string     `form:"cursor"`
+}
+
+type ProductPage struct {
+	Data       []Product `json:"data"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Total      int       `json:"total"`
+}
+
+const (
+	LiveStreamSortNewest  = "newest"
+	LiveStreamSortOldest  = "oldest"
+	LiveStreamSortViewers = "viewers"
+)
+
+// LiveStreamFilter selects a page of livestreams. Bound from the query
+// string of the livestream listing endpoints.
+type LiveStreamFilter struct {
+	SellerID     *int       `form:"seller_id"`
+	IsLive       *bool      `form:"is_live"`
+	StartedAfter *time.Time `form:"started_after"`
+	Sort         string     `form:"sort" binding:"omitempty,oneof=newest oldest viewers"`
+	Limit        int        `form:"limit" binding:"omitempty,min=1"`
+	Cursor       string     `form:"cursor"`
+}
+
+type LiveStreamPage struct {
+	Data       []LiveStream
+	NextCursor string
+	Total      int
+}
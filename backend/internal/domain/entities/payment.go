@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusSucceeded  = "succeeded"
+	PaymentStatusFailed     = "failed"
+	PaymentStatusRefunded   = "refunded"
+)
+
+// Webhook event types every PaymentProvider normalises its notifications to.
+const (
+	PaymentEventAuthorized = "payment.authorized"
+	PaymentEventSucceeded  = "payment.succeeded"
+	PaymentEventFailed     = "payment.failed"
+	PaymentEventRefunded   = "payment.refunded"
+)
+
+type Payment struct {
+	ID                int       `json:"id"`
+	OrderID           int       `json:"order_id"`
+	Provider          string    `json:"provider"`
+	ProviderPaymentID string    `json:"provider_payment_id"`
+	Amount            float64   `json:"amount"`
+	Currency          string    `json:"currency"`
+	Status            string    `json:"status"`
+	ClientSecret      string    `json:"client_secret,omitempty"`
+	CheckoutURL       string    `json:"checkout_url,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type PaymentIntentParams struct {
+	OrderID     int
+	Amount      float64
+	Currency    string
+	Description string
+}
+
+// PaymentIntent is what a provider hands back when a payment is started. The
+// client uses ClientSecret or CheckoutURL to complete it.
+type PaymentIntent struct {
+	ProviderPaymentID string
+	Status            string
+	ClientSecret      string
+	CheckoutURL       string
+}
+
+// PaymentWebhookEvent is a verified provider notification. EventID is unique
+// per provider and is used to process every event at most once.
+type PaymentWebhookEvent struct {
+	EventID           string
+	Type              string
+	ProviderPaymentID string
+	Payload           []byte
+}
+
+// PaymentUpdate is the state change caused by one webhook event. OrderEvent
+// is nil when the order status is unaffected.
+type PaymentUpdate struct {
+	PaymentID     int
+	PaymentStatus string
+	OrderEvent    *OrderEvent
+}
@@ -3,28 +3,49 @@ package entities
 import "time"
 
 type Product struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	SellerID    int       `json:"seller_id"`
-	Images      []Image   `json:"images,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       float64          `json:"price"`
+	SellerID    int              `json:"seller_id"`
+	Stock       int              `json:"stock"`
+	Images      []Image          `json:"images,omitempty"`
+	Options     []ProductOption  `json:"options,omitempty"`
+	Variants    []ProductVariant `json:"variants,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
 }
 
 type Image struct {
 	ID        int    `json:"id"`
 	ProductID int    `json:"product_id"`
+	VariantID *int   `json:"variant_id,omitempty"`
 	ImageURL  string `json:"image_url"`
 }
 
 type PinnedProduct struct {
 	ID              int       `json:"id"`
 	ProductID       int       `json:"product_id"`
+	VariantID       *int      `json:"variant_id,omitempty"`
 	SellerID        int       `json:"seller_id"`
 	SimilarityScore float64   `json:"similarity_score"`
 	IsPinned        bool      `json:"is_pinned"`
 	PinnedAt        time.Time `json:"pinned_at"`
 	Product         *Product  `json:"product,omitempty"`
-}
\ No newline at end of file
+}
+
+// StockLevel is a product's or variant's stock as seen by buyers. Reserved
+// counts units held in carts by unexpired reservations. For a product with
+// variants the product-level figures are totals across its variants.
+type StockLevel struct {
+	ProductID int  `json:"product_id"`
+	VariantID *int `json:"variant_id,omitempty"`
+	SellerID  int  `json:"seller_id"`
+	Stock     int  `json:"stock"`
+	Reserved  int  `json:"reserved"`
+	Available int  `json:"available"`
+}
+
+type UpdateStockRequest struct {
+	Stock *int `json:"stock" binding:"required,min=0"`
+}
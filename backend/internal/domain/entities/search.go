@@ -0,0 +1,55 @@
+package entities
+
+// ProductSearchParams is a full-text product search. Bound from the query
+// string of the search endpoint.
+type ProductSearchParams struct {
+	Query    string `form:"q" binding:"required"`
+	SellerID *int   `form:"seller_id"`
+	// LiveStreamID limits results to products pinned in that livestream
+	// while it is live.
+	LiveStreamID *int `form:"livestream_id"`
+	// PinnedLive limits results to products pinned in any live stream.
+	PinnedLive bool `form:"pinned_live"`
+	Limit      int  `form:"limit" binding:"omitempty,min=1"`
+}
+
+// ProductSearchHit is a matching product with its rank and the matched
+// terms highlighted with <mark> tags.
+type ProductSearchHit struct {
+	Product       Product `json:"product"`
+	Rank          float64 `json:"rank"`
+	NameHighlight string  `json:"name_highlight"`
+	Snippet       string  `json:"snippet,omitempty"`
+}
+
+type ProductSearchResponse struct {
+	Query string             `json:"query"`
+	Data  []ProductSearchHit `json:"data"`
+	Total int                `json:"total"`
+}
+
+// VisualSearchParams is a search by photo. Bound from the multipart form of
+// the visual search endpoint alongside the image itself.
+type VisualSearchParams struct {
+	// SellerIDs is a comma-separated list of sellers to search. When empty,
+	// every seller currently live is searched.
+	SellerIDs string  `form:"seller_ids"`
+	MinScore  float64 `form:"min_score" binding:"omitempty,min=0,max=1"`
+	Limit     int     `form:"limit" binding:"omitempty,min=1"`
+}
+
+// VisualSearchHit is a catalogue product that looks like the searched
+// photo, with the best similarity any of its detections reached.
+type VisualSearchHit struct {
+	Product         Product `json:"product"`
+	SimilarityScore float64 `json:"similarity_score"`
+	Confidence      float64 `json:"confidence"`
+}
+
+type VisualSearchResponse struct {
+	Data []VisualSearchHit `json:"data"`
+	// SearchedSellers lists the sellers whose indexes were queried and
+	// FailedSellers those whose index could not be queried.
+	SearchedSellers []int `json:"searched_sellers"`
+	FailedSellers   []int `json:"failed_sellers,omitempty"`
+}
@@ -0,0 +1,213 @@
+package entities
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+)
+
+// Signaling protocol versions understood by the server. Clients announce the
+// version they speak in the join payload and the server answers with the
+// version it will use for the rest of the session.
+const (
+	SignalingProtocolMinVersion = 1
+	SignalingProtocolVersion    = 3
+	// FrameProtocolVersion is the first version in which the publisher may
+	// send detection frames as binary messages.
+	FrameProtocolVersion = 3
+)
+
+const (
+	MessageTypeJoin               = "join"
+	MessageTypeJoined             = "joined"
+	MessageTypeUserJoined         = "user_joined"
+	MessageTypeUserLeft           = "user_left"
+	MessageTypeWebRTCOffer        = "webrtc_offer"
+	MessageTypeWebRTCAnswer       = "webrtc_answer"
+	MessageTypeWebRTCICECandidate = "webrtc_ice_candidate"
+	MessageTypeChat               = "chat"
+	MessageTypeReaction           = "reaction"
+	MessageTypeSellerLive         = "seller_live"
+	MessageTypeSellerOffline      = "seller_offline"
+	MessageTypeProductPinned      = "product_pinned"
+	MessageTypeProductUnpinned    = "product_unpinned"
+	MessageTypeStockUpdated       = "stock_updated"
+	MessageTypeDetectionResult    = "detection_result"
+	MessageTypePinSuggestion      = "pin_suggestion"
+	MessageTypeTrainingProgress   = "training_progress"
+	MessageTypeTrainingCompleted  = "training_completed"
+	MessageTypeTrainingFailed     = "training_failed"
+	MessageTypeError              = "error"
+	// MessageTypeFrame names binary frame messages in error replies.
+	MessageTypeFrame = "frame"
+)
+
+// Error codes sent back to clients in an error message payload.
+const (
+	SignalingErrInvalidMessage     = "invalid_message"
+	SignalingErrUnknownType        = "unknown_type"
+	SignalingErrInvalidPayload     = "invalid_payload"
+	SignalingErrUnsupportedVersion = "unsupported_version"
+	SignalingErrNotJoined          = "not_joined"
+	SignalingErrForbidden          = "forbidden"
+	SignalingErrClientIDTaken      = "client_id_taken"
+	SignalingErrDeliveryFailed     = "delivery_failed"
+	SignalingErrDetectionFailed    = "detection_failed"
+)
+
+// A binary message on a signaling WebSocket carries one JPEG frame of the
+// publisher's stream for detection, behind a fixed header:
+//
+//	byte 0      FrameMessageKind
+//	bytes 1-4   sequence number, big-endian
+//	bytes 5-12  capture time in Unix milliseconds, big-endian
+//	bytes 13-   JPEG data
+const (
+	FrameMessageKind       byte = 0x01
+	FrameMessageHeaderSize      = 13
+	// MaxSignalingMessageSize caps any message read from a signaling
+	// WebSocket, frames included.
+	MaxSignalingMessageSize = 2 << 20
+)
+
+// FrameMessage is a decoded binary frame message.
+type FrameMessage struct {
+	Sequence   uint32
+	CapturedAt time.Time
+	JPEG       []byte
+}
+
+func ParseFrameMessage(data []byte) (*FrameMessage, error) {
+	if len(data) <= FrameMessageHeaderSize {
+		return nil, NewSignalingError(SignalingErrInvalidPayload, "frame is shorter than its header")
+	}
+	if data[0] != FrameMessageKind {
+		return nil, NewSignalingError(SignalingErrInvalidPayload, "unknown binary message kind")
+	}
+	return &FrameMessage{
+		Sequence:   binary.BigEndian.Uint32(data[1:5]),
+		CapturedAt: time.UnixMilli(int64(binary.BigEndian.Uint64(data[5:13]))),
+		JPEG:       data[FrameMessageHeaderSize:],
+	}, nil
+}
+
+// SignalingEnvelope is an inbound frame read from a signaling WebSocket. Data
+// is kept raw until the type is known so it can be decoded into the matching
+// payload struct.
+type SignalingEnvelope struct {
+	ID       string          `json:"id,omitempty"`
+	Type     string          `json:"type"`
+	Room     string          `json:"room"`
+	ClientID string          `json:"client_id,omitempty"`
+	To       string          `json:"to,omitempty"`
+	From     string          `json:"from,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+type JoinPayload struct {
+	ClientID        string `json:"client_id"`
+	Role            string `json:"role"`
+	ProtocolVersion int    `json:"protocol_version,omitempty"`
+}
+
+type JoinedPayload struct {
+	Status          string `json:"status"`
+	ClientID        string `json:"client_id"`
+	ProtocolVersion int    `json:"protocol_version"`
+}
+
+type ClientPayload struct {
+	ClientID string `json:"client_id"`
+}
+
+type SessionDescriptionPayload struct {
+	Type string `json:"type,omitempty"`
+	SDP  string `json:"sdp"`
+}
+
+type ICECandidatePayload struct {
+	Candidate        string  `json:"candidate"`
+	SDPMid           *string `json:"sdpMid,omitempty"`
+	SDPMLineIndex    *uint16 `json:"sdpMLineIndex,omitempty"`
+	UsernameFragment *string `json:"usernameFragment,omitempty"`
+}
+
+type ChatPayload struct {
+	Message   string `json:"message"`
+	Username  string `json:"username,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+type ReactionPayload struct {
+	Emoji string `json:"emoji"`
+}
+
+type SellerStatusPayload struct {
+	SellerID string `json:"seller_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+type ProductPinnedPayload struct {
+	ProductID       int     `json:"product_id"`
+	VariantID       *int    `json:"variant_id,omitempty"`
+	ProductName     string  `json:"product_name,omitempty"`
+	Price           float64 `json:"price,omitempty"`
+	SimilarityScore float64 `json:"similarity_score,omitempty"`
+}
+
+// PinSuggestionPayload offers the seller a product the stream has shown
+// consistently, for them to pin with the usual pin endpoint.
+type PinSuggestionPayload struct {
+	ProductID       int     `json:"product_id"`
+	ProductName     string  `json:"product_name"`
+	Price           float64 `json:"price"`
+	SimilarityScore float64 `json:"similarity_score"`
+	Frames          int     `json:"frames"`
+}
+
+type ProductUnpinnedPayload struct {
+	ProductID int `json:"product_id"`
+}
+
+type StockUpdatedPayload struct {
+	ProductID int  `json:"product_id"`
+	VariantID *int `json:"variant_id,omitempty"`
+	Stock     int  `json:"stock"`
+	Available int  `json:"available"`
+}
+
+// DetectionResultPayload carries the products detected in a frame of the
+// publisher's stream, either one the server sampled from their video track
+// or one they sent over the socket.
+type DetectionResultPayload struct {
+	Source     string              `json:"source"`
+	CapturedAt time.Time           `json:"captured_at"`
+	Result     *PredictionResponse `json:"result"`
+	// Sequence is the number of the frame the publisher sent, and Dropped
+	// how many of their frames were skipped since the previous result
+	// because detection was behind.
+	Sequence *uint32 `json:"sequence,omitempty"`
+	Dropped  int     `json:"dropped,omitempty"`
+}
+
+type ErrorPayload struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	MessageID   string `json:"message_id,omitempty"`
+	MessageType string `json:"message_type,omitempty"`
+}
+
+// SignalingError is returned by message handlers when a frame is rejected. It
+// carries everything needed to build the error reply for the sender.
+type SignalingError struct {
+	Code    string
+	Message string
+}
+
+func (e *SignalingError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func NewSignalingError(code, message string) *SignalingError {
+	return &SignalingError{Code: code, Message: message}
+}
@@ -0,0 +1,75 @@
+package entities
+
+import "time"
+
+// Training job statuses. A seller has at most one job queued or running.
+const (
+	TrainingJobQueued    = "queued"
+	TrainingJobRunning   = "running"
+	TrainingJobCompleted = "completed"
+	TrainingJobFailed    = "failed"
+)
+
+// TrainingJob is one run of the ML service's training pipeline for a
+// seller's catalogue.
+type TrainingJob struct {
+	ID       int `json:"id"`
+	SellerID int `json:"seller_id"`
+	// ProductID is the product whose change prompted the job, if any.
+	// Training always covers the seller's whole catalogue.
+	ProductID  *int       `json:"product_id,omitempty"`
+	FineTune   bool       `json:"fine_tune"`
+	Status     string     `json:"status"`
+	Progress   int        `json:"progress"`
+	Message    string     `json:"message"`
+	Error      *string    `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the job is still queued or running.
+func (j *TrainingJob) Active() bool {
+	return j.Status == TrainingJobQueued || j.Status == TrainingJobRunning
+}
+
+type TrainingJobRequest struct {
+	FineTune bool `json:"fine_tune"`
+}
+
+// TrainingJobFilter selects a page of a seller's training jobs, newest
+// first. Bound from the query string of the training job listing.
+type TrainingJobFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=queued running completed failed"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"`
+}
+
+type TrainingJobPage struct {
+	Data       []TrainingJob `json:"data"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// CatalogueState tracks whether a seller's ML index is behind their
+// catalogue.
+type CatalogueState struct {
+	SellerID int `json:"seller_id"`
+	// AutoRetrain is whether changes are retrained automatically once the
+	// catalogue has been quiet for a while.
+	AutoRetrain bool `json:"auto_retrain"`
+	// DirtySince is the first change the index does not cover yet, nil
+	// when it is up to date.
+	DirtySince *time.Time `json:"dirty_since,omitempty"`
+	// ChangedAt is the latest change the index does not cover yet.
+	ChangedAt *time.Time `json:"changed_at,omitempty"`
+	// FailedAttempts counts the trainings that failed since the catalogue
+	// last changed; each one doubles the wait before the next automatic
+	// retrain.
+	FailedAttempts int       `json:"failed_attempts"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type RetrainSettingsRequest struct {
+	AutoRetrain *bool `json:"auto_retrain" binding:"required"`
+}
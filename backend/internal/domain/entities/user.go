@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+type User struct {
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	PasswordHash string    `json:"-"`
+	Seller       *Seller   `json:"seller,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Seller struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	ShopName  string    `json:"shop_name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Principal is the authenticated caller derived from an access token.
+// SellerID is zero for users without a seller account.
+type Principal struct {
+	UserID   int    `json:"user_id"`
+	Email    string `json:"email"`
+	SellerID int    `json:"seller_id,omitempty"`
+}
+
+func (p *Principal) IsSeller() bool {
+	return p != nil && p.SellerID != 0
+}
+
+type RefreshClaims struct {
+	UserID    int
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	Name     string `json:"name" binding:"required"`
+	ShopName string `json:"shop_name"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type BecomeSellerRequest struct {
+	ShopName string `json:"shop_name" binding:"required"`
+}
+
+type AuthResponse struct {
+	User                  *User     `json:"user"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductOption is an axis a product varies along, such as size or colour,
+// with the values sellers may pick from.
+type ProductOption struct {
+	ID        int      `json:"id"`
+	ProductID int      `json:"product_id"`
+	Name      string   `json:"name" binding:"required"`
+	Values    []string `json:"values" binding:"required,min=1"`
+	Position  int      `json:"position"`
+}
+
+// ProductVariant is one purchasable combination of option values with its
+// own SKU, price and stock.
+type ProductVariant struct {
+	ID        int               `json:"id"`
+	ProductID int               `json:"product_id"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options"`
+	Price     float64           `json:"price"`
+	Stock     int               `json:"stock"`
+	Available int               `json:"available"`
+	Images    []Image           `json:"images,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Name renders the variant's options in a stable order, e.g. "colour: red,
+// size: M", for order lines and notifications.
+func (v *ProductVariant) Name() string {
+	keys := make([]string, 0, len(v.Options))
+	for key := range v.Options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + ": " + v.Options[key]
+	}
+	return strings.Join(parts, ", ")
+}
+
+type ProductOptionsRequest struct {
+	Options []ProductOption `json:"options" binding:"dive"`
+}
+
+// VariantRequest creates or updates a variant. A nil Price falls back to the
+// product's price on create and leaves the price unchanged on update.
+type VariantRequest struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options" binding:"required"`
+	Price   *float64          `json:"price" binding:"omitempty,gt=0"`
+	Stock   *int              `json:"stock" binding:"omitempty,min=0"`
+}
+
+type ProductVariantsResponse struct {
+	Options  []ProductOption  `json:"options"`
+	Variants []ProductVariant `json:"variants"`
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type WebRTCMessage struct {
+	ID       string      `json:"id,omitempty"`
 	Type     string      `json:"type"`
 	Data     interface{} `json:"data"`
 	Room     string      `json:"room"`
@@ -25,7 +26,30 @@ type Client struct {
 	Role          string
 	RoomID        string
 	LocalTracks   []*webrtc.TrackLocalStaticRTP
+	ProtocolVersion int
 	ConnectedAt   time.Time
+	// Deliver, when set, receives the client's messages instead of Conn. It
+	// lets in-process participants such as the frame sampler join a room.
+	Deliver func(v interface{}) error
+
+	writeMu sync.Mutex
+}
+
+// WriteJSON serialises writes to the client's connection. The WebSocket
+// library allows only one concurrent writer, and room broadcasts can come
+// from request handlers as well as from other clients' read loops.
+func (c *Client) WriteJSON(v interface{}) error {
+	if c.Deliver != nil {
+		return c.Deliver(v)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.Conn.WriteJSON(v)
+}
+
+// Reachable reports whether messages can be delivered to the client.
+func (c *Client) Reachable() bool {
+	return c.Conn != nil || c.Deliver != nil
 }
 
 type Room struct {
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type AutoPinRepository interface {
+	// FindSettings returns entities.ErrNotFound for sellers who have not
+	// saved settings.
+	FindSettings(ctx context.Context, sellerID int) (*entities.AutoPinSettings, error)
+	SaveSettings(ctx context.Context, settings *entities.AutoPinSettings) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"time"
+)
+
+type CartRepository interface {
+	// FindOrCreateByUserID returns the user's cart with its items and their
+	// current product details, creating an empty cart on first use.
+	FindOrCreateByUserID(ctx context.Context, userID int) (*entities.Cart, error)
+	// AddItem inserts the item or, if the same product was already added
+	// from the same livestream, increases its quantity. The item's full
+	// quantity is reserved for ttl; ErrInsufficientStock is returned and
+	// nothing is changed if not enough stock is available.
+	AddItem(ctx context.Context, item *entities.CartItem, ttl time.Duration) error
+	// UpdateItemQuantity changes the quantity and renews the reservation
+	// like AddItem.
+	UpdateItemQuantity(ctx context.Context, cartID int, itemID int, quantity int, ttl time.Duration) error
+	RemoveItem(ctx context.Context, cartID int, itemID int) error
+	// Checkout stores the orders, removes the given cart items and deducts
+	// the ordered quantities from stock in a single transaction.
+	Checkout(ctx context.Context, cartID int, itemIDs []int, orders []entities.Order) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"time"
+)
+
+type CatalogueRepository interface {
+	// FindState returns entities.ErrNotFound for sellers whose catalogue
+	// has never changed or been trained.
+	FindState(ctx context.Context, sellerID int) (*entities.CatalogueState, error)
+	SaveAutoRetrain(ctx context.Context, sellerID int, autoRetrain bool) (*entities.CatalogueState, error)
+	// MarkDirty records a change the seller's index does not cover yet.
+	MarkDirty(ctx context.Context, sellerID int) error
+	// MarkFailed records that rebuilding the seller's index failed. The
+	// catalogue stays dirty but without counting as a new change, and every
+	// consecutive failure doubles the wait before it is due again.
+	MarkFailed(ctx context.Context, sellerID int) error
+	// MarkClean records that the seller's index is being rebuilt from the
+	// current catalogue.
+	MarkClean(ctx context.Context, sellerID int) error
+	// FindDue returns the sellers with auto-retraining on whose catalogue
+	// has been dirty and unchanged for at least quiet, backed off after
+	// failed trainings.
+	FindDue(ctx context.Context, quiet time.Duration) ([]int, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type DetectionRepository interface {
+	// Record stores detections, skipping any whose product no longer exists.
+	Record(ctx context.Context, detections []entities.Detection) error
+	// FindAppearances groups a stream's detections into appearances: runs of
+	// detections of the same product no more than gapSeconds apart. They are
+	// ordered by product and then time.
+	FindAppearances(ctx context.Context, liveStreamID int, gapSeconds int, productID *int) ([]entities.ProductAppearance, error)
+}
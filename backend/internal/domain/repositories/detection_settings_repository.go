@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type DetectionSettingsRepository interface {
+	// FindSettings returns entities.ErrNotFound for sellers who have not
+	// saved settings.
+	FindSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error)
+	SaveSettings(ctx context.Context, settings *entities.DetectionSettings) error
+	DeleteSettings(ctx context.Context, sellerID int) error
+}
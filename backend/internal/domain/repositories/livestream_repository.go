@@ -4,9 +4,10 @@ import "live-shopping-ai/backend/internal/domain/entities"
 
 type LiveStreamRepository interface {
 	CreateLiveStream(stream *entities.LiveStream) error
-	GetLiveStreamBySellerID(sellerID string) (*entities.LiveStream, error)
-	GetActiveLiveStreams() ([]entities.LiveStream, error)
-	UpdateLiveStreamStatus(sellerID string, isLive bool) error
-	UpdateViewerCount(sellerID string, count int) error
-	EndLiveStream(sellerID string) error
+	GetLiveStreamByID(id int) (*entities.LiveStream, error)
+	GetLiveStreamBySellerID(sellerID int) (*entities.LiveStream, error)
+	ListLiveStreams(filter *entities.LiveStreamFilter) (*entities.LiveStreamPage, error)
+	UpdateLiveStreamStatus(sellerID int, isLive bool) error
+	UpdateViewerCount(sellerID int, count int) error
+	EndLiveStream(sellerID int) error
 }
\ No newline at end of file
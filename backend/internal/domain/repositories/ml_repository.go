@@ -1,15 +1,23 @@
 package repositories
 
 import (
+	"context"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"mime/multipart"
 )
 
 type MLRepository interface {
-	TrainModel(sellerID string) (*entities.TrainingResponse, error)
-	PredictProduct(sellerID string, imageData []byte) (*entities.PredictionResponse, error)
-	GetTrainingStatus(sellerID string) (map[string]interface{}, error)
-	ProcessStreamFrame(sellerID string, imageData []byte) (*entities.PredictionResponse, error)
+	// Errors meaning the ML service is down or failing fast wrap
+	// entities.ErrMLUnavailable. Predictions are made with the seller's
+	// detection settings, or the ML service's defaults when they are nil.
+	TrainModel(ctx context.Context, sellerID int, fineTune bool) (*entities.TrainingResponse, error)
+	PredictProduct(ctx context.Context, sellerID int, imageData []byte, settings *entities.DetectionSettings) (*entities.PredictionResponse, error)
+	// PredictBatch predicts several frames in one request. Results are in
+	// the order of the frames; a frame that could not be predicted carries
+	// its own error rather than failing the batch.
+	PredictBatch(ctx context.Context, sellerID int, frames [][]byte, settings *entities.DetectionSettings) ([]entities.FramePrediction, error)
+	GetTrainingStatus(ctx context.Context, sellerID int) (map[string]interface{}, error)
+	ProcessStreamFrame(ctx context.Context, sellerID int, imageData []byte, settings *entities.DetectionSettings) (*entities.PredictionResponse, error)
 }
 
 type StorageRepository interface {
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type OrderRepository interface {
+	// FindByID returns the order with its items and status history.
+	FindByID(ctx context.Context, id int) (*entities.Order, error)
+	FindByUserID(ctx context.Context, userID int) ([]entities.Order, error)
+	// FindBySellerID lists a seller's orders, optionally limited to one
+	// status when status is non-empty.
+	FindBySellerID(ctx context.Context, sellerID int, status string) ([]entities.Order, error)
+	// UpdateStatus moves the order from one status to another and records
+	// the event. It fails with ErrConflict if the order is no longer in the
+	// from status.
+	UpdateStatus(ctx context.Context, event *entities.OrderEvent) error
+}
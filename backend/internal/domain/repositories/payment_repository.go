@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"net/http"
+)
+
+// PaymentProvider is a payment service provider gateway. Implementations
+// translate their own webhook formats into entities.PaymentWebhookEvent.
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, params *entities.PaymentIntentParams) (*entities.PaymentIntent, error)
+	Capture(ctx context.Context, providerPaymentID string) error
+	Refund(ctx context.Context, providerPaymentID string, amount float64) error
+	VerifyWebhook(payload []byte, header http.Header) (*entities.PaymentWebhookEvent, error)
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *entities.Payment) error
+	FindByProviderPaymentID(ctx context.Context, provider string, providerPaymentID string) (*entities.Payment, error)
+	// FindLatestByOrderID returns the most recent payment attempt for an
+	// order.
+	FindLatestByOrderID(ctx context.Context, orderID int) (*entities.Payment, error)
+	// ApplyEvent records the webhook event and applies the update in one
+	// transaction. It reports false without changing anything if the event
+	// was already processed.
+	ApplyEvent(ctx context.Context, provider string, event *entities.PaymentWebhookEvent, update *entities.PaymentUpdate) (bool, error)
+}
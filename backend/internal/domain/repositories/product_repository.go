@@ -6,9 +6,15 @@ import (
 )
 
 type ProductRepository interface {
-	FindAll(ctx context.Context) ([]entities.Product, error)
+	// List returns one page of products matching the filter, ordered by its
+	// sort and continuing after its cursor.
+	List(ctx context.Context, filter *entities.ProductFilter) (*entities.ProductPage, error)
 	FindByID(ctx context.Context, id int) (*entities.Product, error)
-	FindBySellerID(ctx context.Context, sellerID int) ([]entities.Product, error)
+	// FindByIDs returns the products that exist among ids, in no particular
+	// order.
+	FindByIDs(ctx context.Context, ids []int) ([]entities.Product, error)
+	// Search runs a full-text search; tsQuery is in to_tsquery syntax.
+	Search(ctx context.Context, tsQuery string, params *entities.ProductSearchParams) (*entities.ProductSearchResponse, error)
 	Create(ctx context.Context, product *entities.Product) error
 	Update(ctx context.Context, product *entities.Product) error
 	Delete(ctx context.Context, id int) error
@@ -16,8 +22,13 @@ type ProductRepository interface {
 }
 
 type PinnedProductRepository interface {
-	FindPinnedBySellerID(ctx context.Context, sellerID string) ([]entities.PinnedProduct, error)
+	FindPinnedBySellerID(ctx context.Context, sellerID int) ([]entities.PinnedProduct, error)
+	IsPinned(ctx context.Context, productID int, sellerID int) (bool, error)
 	PinProduct(ctx context.Context, pinData *entities.PinnedProduct) error
-	UnpinProduct(ctx context.Context, productID int, sellerID string) error
-	UnpinAllProducts(ctx context.Context, sellerID string) (int64, error)
+	// PinProductLimited pins the product alongside the seller's other pins,
+	// unpinning the oldest ones beyond maxPinned. It returns the IDs of the
+	// products it unpinned.
+	PinProductLimited(ctx context.Context, pinData *entities.PinnedProduct, maxPinned int) ([]int, error)
+	UnpinProduct(ctx context.Context, productID int, sellerID int) error
+	UnpinAllProducts(ctx context.Context, sellerID int) (int64, error)
 }
\ No newline at end of file
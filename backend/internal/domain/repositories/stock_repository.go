@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type StockRepository interface {
+	// FindLevels returns a product-level entry for every product and one
+	// entry per variant for products that have variants.
+	FindLevels(ctx context.Context, productIDs []int) ([]entities.StockLevel, error)
+	SetStock(ctx context.Context, productID int, stock int) error
+	SetVariantStock(ctx context.Context, variantID int, stock int) error
+	// ReleaseExpired drops cart items whose reservation has expired and
+	// returns the IDs of the products whose stock was released.
+	ReleaseExpired(ctx context.Context) ([]int, error)
+}
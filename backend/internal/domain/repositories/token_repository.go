@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"time"
+)
+
+type TokenRepository interface {
+	IssueStreamToken(claims *entities.StreamClaims) (string, error)
+	VerifyStreamToken(token string) (*entities.StreamClaims, error)
+	IssueAccessToken(principal *entities.Principal, expiresAt time.Time) (string, error)
+	VerifyAccessToken(token string) (*entities.Principal, error)
+	IssueRefreshToken(claims *entities.RefreshClaims) (string, error)
+	VerifyRefreshToken(token string) (*entities.RefreshClaims, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"time"
+)
+
+type TrainingJobRepository interface {
+	// Create stores a queued job leased to runnerID. It returns
+	// entities.ErrConflict when the seller already has a job queued or
+	// running.
+	Create(ctx context.Context, job *entities.TrainingJob, runnerID string, lease time.Duration) error
+	FindByID(ctx context.Context, id int) (*entities.TrainingJob, error)
+	List(ctx context.Context, sellerID int, filter *entities.TrainingJobFilter) (*entities.TrainingJobPage, error)
+	// ClaimAbandoned leases every job still queued or running whose lease
+	// has run out to runnerID and returns them, oldest first. A job is
+	// claimed by at most one caller.
+	ClaimAbandoned(ctx context.Context, runnerID string, lease time.Duration) ([]entities.TrainingJob, error)
+	// RenewLease extends runnerID's lease on an active job. It reports false
+	// when the job has finished or is no longer leased to runnerID.
+	RenewLease(ctx context.Context, id int, runnerID string, lease time.Duration) (bool, error)
+	// Update saves the job's status, progress, message and error, stamping
+	// when it started and finished as its status moves on.
+	Update(ctx context.Context, job *entities.TrainingJob) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"time"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *entities.User) error
+	FindByID(ctx context.Context, id int) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+}
+
+type SellerRepository interface {
+	Create(ctx context.Context, seller *entities.Seller) error
+	FindByID(ctx context.Context, id int) (*entities.Seller, error)
+	FindByUserID(ctx context.Context, userID int) (*entities.Seller, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, userID int, tokenID string, expiresAt time.Time) error
+	// Revoke marks a token as used and reports whether it was still active,
+	// so a refresh token can only be exchanged once.
+	Revoke(ctx context.Context, tokenID string) (bool, error)
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
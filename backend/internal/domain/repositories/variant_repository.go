@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+)
+
+type VariantRepository interface {
+	FindOptions(ctx context.Context, productID int) ([]entities.ProductOption, error)
+	// ReplaceOptions swaps a product's option axes for the given ones.
+	ReplaceOptions(ctx context.Context, productID int, options []entities.ProductOption) error
+	// FindByProductID returns the product's variants with their images and
+	// currently available stock.
+	FindByProductID(ctx context.Context, productID int) ([]entities.ProductVariant, error)
+	FindByID(ctx context.Context, id int) (*entities.ProductVariant, error)
+	Create(ctx context.Context, variant *entities.ProductVariant) error
+	Update(ctx context.Context, variant *entities.ProductVariant) error
+	Delete(ctx context.Context, id int) error
+	AddImages(ctx context.Context, productID int, variantID int, imageURLs []string) error
+}
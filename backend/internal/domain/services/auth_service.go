@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthService interface {
+	Register(ctx context.Context, req *entities.RegisterRequest) (*entities.AuthResponse, error)
+	Login(ctx context.Context, req *entities.LoginRequest) (*entities.AuthResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*entities.AuthResponse, error)
+	Logout(ctx context.Context, principal *entities.Principal) error
+	BecomeSeller(ctx context.Context, principal *entities.Principal, req *entities.BecomeSellerRequest) (*entities.AuthResponse, error)
+	GetCurrentUser(ctx context.Context, principal *entities.Principal) (*entities.User, error)
+	Authenticate(accessToken string) (*entities.Principal, error)
+	IssueStreamToken(ctx context.Context, principal *entities.Principal, req *entities.StreamTokenRequest) (*entities.StreamTokenResponse, error)
+	VerifyStreamToken(token string) (*entities.StreamClaims, error)
+}
+
+type authService struct {
+	userRepo         repositories.UserRepository
+	sellerRepo       repositories.SellerRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	tokenRepo        repositories.TokenRepository
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	streamTokenTTL   time.Duration
+}
+
+func NewAuthService(
+	userRepo repositories.UserRepository,
+	sellerRepo repositories.SellerRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	tokenRepo repositories.TokenRepository,
+) AuthService {
+	return &authService{
+		userRepo:         userRepo,
+		sellerRepo:       sellerRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		tokenRepo:        tokenRepo,
+		accessTokenTTL:   15 * time.Minute,
+		refreshTokenTTL:  30 * 24 * time.Hour,
+		streamTokenTTL:   12 * time.Hour,
+	}
+}
+
+func (s *authService) Register(ctx context.Context, req *entities.RegisterRequest) (*entities.AuthResponse, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &entities.User{
+		Email:        strings.TrimSpace(req.Email),
+		Name:         req.Name,
+		PasswordHash: string(hash),
+	}
+	if req.ShopName != "" {
+		user.Seller = &entities.Seller{ShopName: req.ShopName}
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, entities.ErrConflict) {
+			return nil, fmt.Errorf("email is already registered: %w", err)
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+func (s *authService) Login(ctx context.Context, req *entities.LoginRequest) (*entities.AuthResponse, error) {
+	user, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, entities.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Refresh tokens are
+// single use; presenting a revoked one revokes every session of the user.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*entities.AuthResponse, error) {
+	claims, err := s.tokenRepo.VerifyRefreshToken(refreshToken)
+	if err != nil {
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	active, err := s.refreshTokenRepo.Revoke(ctx, claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		if err := s.refreshTokenRepo.RevokeAllForUser(ctx, claims.UserID); err != nil {
+			return nil, err
+		}
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+func (s *authService) Logout(ctx context.Context, principal *entities.Principal) error {
+	return s.refreshTokenRepo.RevokeAllForUser(ctx, principal.UserID)
+}
+
+func (s *authService) BecomeSeller(ctx context.Context, principal *entities.Principal, req *entities.BecomeSellerRequest) (*entities.AuthResponse, error) {
+	if principal.IsSeller() {
+		return nil, fmt.Errorf("user already has a seller account: %w", entities.ErrConflict)
+	}
+
+	seller := &entities.Seller{
+		UserID:   principal.UserID,
+		ShopName: req.ShopName,
+	}
+	if err := s.sellerRepo.Create(ctx, seller); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+func (s *authService) GetCurrentUser(ctx context.Context, principal *entities.Principal) (*entities.User, error) {
+	return s.userRepo.FindByID(ctx, principal.UserID)
+}
+
+func (s *authService) Authenticate(accessToken string) (*entities.Principal, error) {
+	return s.tokenRepo.VerifyAccessToken(accessToken)
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *entities.User) (*entities.AuthResponse, error) {
+	principal := &entities.Principal{
+		UserID: user.ID,
+		Email:  user.Email,
+	}
+	if user.Seller != nil {
+		principal.SellerID = user.Seller.ID
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(s.accessTokenTTL)
+	accessToken, err := s.tokenRepo.IssueAccessToken(principal, accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	refreshClaims := &entities.RefreshClaims{
+		UserID:    user.ID,
+		TokenID:   tokenID,
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, user.ID, tokenID, refreshClaims.ExpiresAt); err != nil {
+		return nil, err
+	}
+	refreshToken, err := s.tokenRepo.IssueRefreshToken(refreshClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AuthResponse{
+		User:                  user,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshClaims.ExpiresAt,
+	}, nil
+}
+
+// IssueStreamToken issues the token used to open a signaling WebSocket.
+// Publisher and dashboard tokens are only issued to sellers, for their own
+// room; viewers may be anonymous.
+func (s *authService) IssueStreamToken(ctx context.Context, principal *entities.Principal, req *entities.StreamTokenRequest) (*entities.StreamTokenResponse, error) {
+	claims := &entities.StreamClaims{
+		Role:      req.Role,
+		ExpiresAt: time.Now().Add(s.streamTokenTTL),
+	}
+
+	switch req.Role {
+	case entities.RolePublisher, entities.RoleDashboard:
+		if !principal.IsSeller() {
+			return nil, fmt.Errorf("%s tokens require a seller account: %w", req.Role, entities.ErrForbidden)
+		}
+		claims.SellerID = principal.SellerID
+		claims.Subject = entities.SellerRoomID(principal.SellerID)
+	case entities.RoleViewer:
+		if principal != nil {
+			claims.Subject = "user-" + strconv.Itoa(principal.UserID)
+			break
+		}
+		subject, err := randomHex(8)
+		if err != nil {
+			return nil, err
+		}
+		claims.Subject = "viewer-" + subject
+	default:
+		return nil, fmt.Errorf("unknown role %q: %w", req.Role, entities.ErrInvalidInput)
+	}
+
+	token, err := s.tokenRepo.IssueStreamToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.StreamTokenResponse{
+		Token:     token,
+		Role:      claims.Role,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
+
+func (s *authService) VerifyStreamToken(token string) (*entities.StreamClaims, error) {
+	return s.tokenRepo.VerifyStreamToken(token)
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
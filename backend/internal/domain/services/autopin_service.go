@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type AutoPinService interface {
+	GetSettings(ctx context.Context, sellerID int) (*entities.AutoPinSettings, error)
+	UpdateSettings(ctx context.Context, sellerID int, req *entities.AutoPinSettingsRequest) (*entities.AutoPinSettings, error)
+	// Observe feeds the predictions for one frame of the seller's stream to
+	// the seller's policy, which pins or suggests a product once enough
+	// consecutive frames agree on it.
+	Observe(ctx context.Context, sellerID int, result *entities.PredictionResponse)
+}
+
+type autoPinService struct {
+	autoPinRepo repositories.AutoPinRepository
+	productRepo repositories.ProductRepository
+	pinnedRepo  repositories.PinnedProductRepository
+	webrtcRepo  repositories.WebRTCRepository
+	// settingsTTL is how long a seller's policy is used before it is
+	// reloaded, so updates made through another replica reach this one.
+	settingsTTL time.Duration
+
+	mu     sync.Mutex
+	states map[int]*autoPinState
+}
+
+func NewAutoPinService(
+	autoPinRepo repositories.AutoPinRepository,
+	productRepo repositories.ProductRepository,
+	pinnedRepo repositories.PinnedProductRepository,
+	webrtcRepo repositories.WebRTCRepository,
+) AutoPinService {
+	return &autoPinService{
+		autoPinRepo: autoPinRepo,
+		productRepo: productRepo,
+		pinnedRepo:  pinnedRepo,
+		webrtcRepo:  webrtcRepo,
+		settingsTTL: settingsCacheTTL(),
+		states:      make(map[int]*autoPinState),
+	}
+}
+
+// autoPinState is a seller's policy and what it has seen so far. The mutex
+// keeps frames of the same seller from being judged concurrently.
+type autoPinState struct {
+	mu        sync.Mutex
+	settings  *entities.AutoPinSettings
+	loadedAt  time.Time
+	candidate int
+	streak    int
+	// lastActed is when each product was last pinned or suggested.
+	lastActed map[int]time.Time
+}
+
+func (s *autoPinService) GetSettings(ctx context.Context, sellerID int) (*entities.AutoPinSettings, error) {
+	settings, err := s.autoPinRepo.FindSettings(ctx, sellerID)
+	if errors.Is(err, entities.ErrNotFound) {
+		return entities.DefaultAutoPinSettings(sellerID), nil
+	}
+	return settings, err
+}
+
+func (s *autoPinService) UpdateSettings(ctx context.Context, sellerID int, req *entities.AutoPinSettingsRequest) (*entities.AutoPinSettings, error) {
+	settings := &entities.AutoPinSettings{
+		SellerID:             sellerID,
+		Mode:                 req.Mode,
+		MinSimilarity:        req.MinSimilarity,
+		MinConsecutiveFrames: req.MinConsecutiveFrames,
+		CooldownSeconds:      req.CooldownSeconds,
+		MaxPinned:            req.MaxPinned,
+	}
+	if err := s.autoPinRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	// Start the new policy from scratch rather than judging it by frames
+	// seen under the old one.
+	state := s.state(sellerID)
+	state.mu.Lock()
+	state.settings, state.loadedAt = settings, time.Now()
+	state.candidate, state.streak = 0, 0
+	state.mu.Unlock()
+
+	return settings, nil
+}
+
+func (s *autoPinService) state(sellerID int) *autoPinState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[sellerID]
+	if !ok {
+		state = &autoPinState{lastActed: make(map[int]time.Time)}
+		s.states[sellerID] = state
+	}
+	return state
+}
+
+// reloadSettings refreshes the state's policy once it is due, restarting the
+// streak if it was changed elsewhere. The settings are loaded without holding
+// the state's lock and dropped if the policy was replaced meanwhile. On
+// failure the policy in use is kept until the next reload is due.
+func (s *autoPinService) reloadSettings(ctx context.Context, sellerID int, state *autoPinState) {
+	state.mu.Lock()
+	loadedAt := state.loadedAt
+	due := state.settings == nil || time.Since(loadedAt) >= s.settingsTTL
+	state.mu.Unlock()
+	if !due {
+		return
+	}
+
+	settings, err := s.GetSettings(ctx, sellerID)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.loadedAt.Equal(loadedAt) {
+		return
+	}
+	if err != nil {
+		log.Printf("Auto-pin: failed to load settings for seller %d: %v", sellerID, err)
+		if state.settings != nil {
+			state.loadedAt = time.Now()
+		}
+		return
+	}
+	if state.settings != nil && !settings.UpdatedAt.Equal(state.settings.UpdatedAt) {
+		state.candidate, state.streak = 0, 0
+	}
+	state.settings, state.loadedAt = settings, time.Now()
+}
+
+func (s *autoPinService) Observe(ctx context.Context, sellerID int, result *entities.PredictionResponse) {
+	state := s.state(sellerID)
+	s.reloadSettings(ctx, sellerID, state)
+
+	settings, productID, score, frames := state.judge(result)
+	if productID == 0 {
+		return
+	}
+	if err := s.act(ctx, settings, productID, score, frames); err != nil {
+		log.Printf("Auto-pin: failed to %s product %d for seller %d: %v", settings.Mode, productID, sellerID, err)
+	}
+}
+
+// judge counts the frame towards the policy's streak and returns the product
+// due to be pinned or suggested, with the policy and the number of frames
+// that agreed on it, or 0 when none is due. Acting on it is left to the
+// caller, so the state is not locked while it talks to the database.
+func (state *autoPinState) judge(result *entities.PredictionResponse) (*entities.AutoPinSettings, int, float64, int) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	settings := state.settings
+	if settings == nil || settings.Mode == entities.AutoPinModeOff {
+		return nil, 0, 0, 0
+	}
+
+	productID, score := bestPrediction(result, settings.MinSimilarity)
+	if productID == 0 {
+		state.candidate, state.streak = 0, 0
+		return nil, 0, 0, 0
+	}
+	if productID == state.candidate {
+		state.streak++
+	} else {
+		state.candidate, state.streak = productID, 1
+	}
+	if state.streak < settings.MinConsecutiveFrames {
+		return nil, 0, 0, 0
+	}
+
+	now := time.Now()
+	if last, ok := state.lastActed[productID]; ok && now.Sub(last) < settings.Cooldown() {
+		return nil, 0, 0, 0
+	}
+
+	frames := state.streak
+	state.streak = 0
+	state.lastActed[productID] = now
+	return settings, productID, score, frames
+}
+
+// bestPrediction returns the highest scoring catalogue product at or above
+// minSimilarity, or 0 when there is none.
+func bestPrediction(result *entities.PredictionResponse, minSimilarity float64) (int, float64) {
+	bestID, bestScore := 0, 0.0
+	if result == nil {
+		return bestID, bestScore
+	}
+	for _, prediction := range result.Predictions {
+		if prediction.SimilarityScore < minSimilarity || prediction.SimilarityScore <= bestScore {
+			continue
+		}
+		productID, err := strconv.Atoi(prediction.ProductID)
+		if err != nil || productID <= 0 {
+			continue
+		}
+		bestID, bestScore = productID, prediction.SimilarityScore
+	}
+	return bestID, bestScore
+}
+
+func (s *autoPinService) act(ctx context.Context, settings *entities.AutoPinSettings, productID int, score float64, frames int) error {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	// The seller's index can lag behind the catalogue.
+	if product.SellerID != settings.SellerID {
+		return nil
+	}
+
+	pinned, err := s.pinnedRepo.IsPinned(ctx, productID, settings.SellerID)
+	if err != nil || pinned {
+		return err
+	}
+
+	roomID := entities.SellerRoomID(settings.SellerID)
+	if settings.Mode == entities.AutoPinModeSuggest {
+		s.sendToPublishers(roomID, entities.WebRTCMessage{
+			Type: entities.MessageTypePinSuggestion,
+			Data: entities.PinSuggestionPayload{
+				ProductID:       product.ID,
+				ProductName:     product.Name,
+				Price:           product.Price,
+				SimilarityScore: score,
+				Frames:          frames,
+			},
+			Room: roomID,
+		})
+		return nil
+	}
+
+	unpinned, err := s.pinnedRepo.PinProductLimited(ctx, &entities.PinnedProduct{
+		ProductID:       productID,
+		SellerID:        settings.SellerID,
+		SimilarityScore: score,
+		IsPinned:        true,
+	}, settings.MaxPinned)
+	if err != nil {
+		return err
+	}
+
+	for _, unpinnedID := range unpinned {
+		s.webrtcRepo.BroadcastToRoom(roomID, entities.WebRTCMessage{
+			Type: entities.MessageTypeProductUnpinned,
+			Data: entities.ProductUnpinnedPayload{ProductID: unpinnedID},
+			Room: roomID,
+		}, "")
+	}
+	s.webrtcRepo.BroadcastToRoom(roomID, entities.WebRTCMessage{
+		Type: entities.MessageTypeProductPinned,
+		Data: entities.ProductPinnedPayload{
+			ProductID:       product.ID,
+			ProductName:     product.Name,
+			Price:           product.Price,
+			SimilarityScore: score,
+		},
+		Room: roomID,
+	}, "")
+	return nil
+}
+
+func (s *autoPinService) sendToPublishers(roomID string, message entities.WebRTCMessage) {
+	for _, client := range s.webrtcRepo.GetRoomClients(roomID) {
+		if client.Role == entities.RolePublisher {
+			message.To = client.ID
+			s.webrtcRepo.SendToClient(roomID, client.ID, message)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+)
+
+type CartService interface {
+	GetCart(ctx context.Context, userID int) (*entities.Cart, error)
+	AddItem(ctx context.Context, userID int, req *entities.AddCartItemRequest) (*entities.Cart, error)
+	UpdateItem(ctx context.Context, userID int, itemID int, quantity int) (*entities.Cart, error)
+	RemoveItem(ctx context.Context, userID int, itemID int) (*entities.Cart, error)
+	Checkout(ctx context.Context, userID int) ([]entities.Order, error)
+}
+
+type cartService struct {
+	cartRepo         repositories.CartRepository
+	productRepo      repositories.ProductRepository
+	variantRepo      repositories.VariantRepository
+	pinnedRepo       repositories.PinnedProductRepository
+	liveStreamRepo   repositories.LiveStreamRepository
+	inventoryService InventoryService
+}
+
+func NewCartService(
+	cartRepo repositories.CartRepository,
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.VariantRepository,
+	pinnedRepo repositories.PinnedProductRepository,
+	liveStreamRepo repositories.LiveStreamRepository,
+	inventoryService InventoryService,
+) CartService {
+	return &cartService{
+		cartRepo:         cartRepo,
+		productRepo:      productRepo,
+		variantRepo:      variantRepo,
+		pinnedRepo:       pinnedRepo,
+		liveStreamRepo:   liveStreamRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+func (s *cartService) GetCart(ctx context.Context, userID int) (*entities.Cart, error) {
+	return s.cartRepo.FindOrCreateByUserID(ctx, userID)
+}
+
+// AddItem only accepts products that are currently pinned in a live stream
+// of the product's own seller, which is what the live room shows viewers.
+// Products with variants must be added as one of their variants. The item's
+// stock is reserved until the reservation TTL runs out.
+func (s *cartService) AddItem(ctx context.Context, userID int, req *entities.AddCartItemRequest) (*entities.Cart, error) {
+	stream, err := s.liveStreamRepo.GetLiveStreamByID(req.LiveStreamID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, fmt.Errorf("livestream %d: %w", req.LiveStreamID, entities.ErrNotFound)
+		}
+		return nil, err
+	}
+	if !stream.IsLive {
+		return nil, fmt.Errorf("%w: livestream %d has ended", entities.ErrInvalidInput, stream.ID)
+	}
+
+	product, err := s.productRepo.FindByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != stream.SellerID {
+		return nil, fmt.Errorf("%w: product %d is not sold in livestream %d", entities.ErrInvalidInput, product.ID, stream.ID)
+	}
+
+	pinned, err := s.pinnedRepo.IsPinned(ctx, product.ID, stream.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	if !pinned {
+		return nil, fmt.Errorf("%w: product %d is not pinned in livestream %d", entities.ErrInvalidInput, product.ID, stream.ID)
+	}
+
+	if err := s.checkVariant(ctx, product.ID, req.VariantID); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	item := &entities.CartItem{
+		CartID:       cart.ID,
+		ProductID:    product.ID,
+		VariantID:    req.VariantID,
+		LiveStreamID: stream.ID,
+		Quantity:     quantity,
+	}
+	if err := s.cartRepo.AddItem(ctx, item, s.inventoryService.ReservationTTL()); err != nil {
+		return nil, err
+	}
+	s.inventoryService.PublishStock(ctx, product.ID)
+
+	return s.cartRepo.FindOrCreateByUserID(ctx, userID)
+}
+
+func (s *cartService) UpdateItem(ctx context.Context, userID int, itemID int, quantity int) (*entities.Cart, error) {
+	cart, err := s.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.UpdateItemQuantity(ctx, cart.ID, itemID, quantity, s.inventoryService.ReservationTTL()); err != nil {
+		return nil, err
+	}
+	s.publishItemStock(ctx, cart, itemID)
+
+	return s.cartRepo.FindOrCreateByUserID(ctx, userID)
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, userID int, itemID int) (*entities.Cart, error) {
+	cart, err := s.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.RemoveItem(ctx, cart.ID, itemID); err != nil {
+		return nil, err
+	}
+	s.publishItemStock(ctx, cart, itemID)
+
+	return s.cartRepo.FindOrCreateByUserID(ctx, userID)
+}
+
+// Checkout turns the cart into one pending order per livestream the items
+// were added from. A livestream belongs to a single seller, so each order is
+// also scoped to one seller.
+func (s *cartService) Checkout(ctx context.Context, userID int) ([]entities.Order, error) {
+	cart, err := s.cartRepo.FindOrCreateByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, fmt.Errorf("%w: cart is empty", entities.ErrInvalidInput)
+	}
+
+	var orders []entities.Order
+	orderIndex := make(map[int]int)
+	itemIDs := make([]int, 0, len(cart.Items))
+
+	for _, item := range cart.Items {
+		idx, ok := orderIndex[item.LiveStreamID]
+		if !ok {
+			liveStreamID := item.LiveStreamID
+			orders = append(orders, entities.Order{
+				UserID:       userID,
+				SellerID:     item.Product.SellerID,
+				LiveStreamID: &liveStreamID,
+				Status:       entities.OrderStatusPending,
+			})
+			idx = len(orders) - 1
+			orderIndex[item.LiveStreamID] = idx
+		}
+
+		productID := item.ProductID
+		orderItem := entities.OrderItem{
+			ProductID:   &productID,
+			VariantID:   item.VariantID,
+			ProductName: item.Product.Name,
+			UnitPrice:   item.UnitPrice(),
+			Quantity:    item.Quantity,
+		}
+		if item.Variant != nil {
+			orderItem.VariantName = item.Variant.Name()
+		}
+		orders[idx].Items = append(orders[idx].Items, orderItem)
+		orders[idx].TotalAmount += orderItem.UnitPrice * float64(item.Quantity)
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	if err := s.cartRepo.Checkout(ctx, cart.ID, itemIDs, orders); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	s.inventoryService.PublishStock(ctx, productIDs...)
+
+	return orders, nil
+}
+
+// checkVariant requires a variant of the product when it has variants and
+// no variant otherwise.
+func (s *cartService) checkVariant(ctx context.Context, productID int, variantID *int) error {
+	if variantID != nil {
+		variant, err := s.variantRepo.FindByID(ctx, *variantID)
+		if err != nil {
+			if errors.Is(err, entities.ErrNotFound) {
+				return fmt.Errorf("%w: variant %d does not exist", entities.ErrInvalidInput, *variantID)
+			}
+			return err
+		}
+		if variant.ProductID != productID {
+			return fmt.Errorf("%w: variant %d is not a variant of product %d", entities.ErrInvalidInput, variant.ID, productID)
+		}
+		return nil
+	}
+
+	variants, err := s.variantRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return fmt.Errorf("%w: product %d requires a variant_id", entities.ErrInvalidInput, productID)
+	}
+	return nil
+}
+
+func (s *cartService) publishItemStock(ctx context.Context, cart *entities.Cart, itemID int) {
+	for _, item := range cart.Items {
+		if item.ID == itemID {
+			s.inventoryService.PublishStock(ctx, item.ProductID)
+			return
+		}
+	}
+}
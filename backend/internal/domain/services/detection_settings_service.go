@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type DetectionSettingsService interface {
+	// GetSettings returns the seller's settings, or the defaults if they
+	// have not saved any. It is called for every frame, so settings are
+	// cached for SETTINGS_CACHE_TTL; the result must not be modified.
+	GetSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error)
+	UpdateSettings(ctx context.Context, sellerID int, req *entities.DetectionSettingsRequest) (*entities.DetectionSettings, error)
+	// ResetSettings goes back to the defaults.
+	ResetSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error)
+}
+
+type detectionSettingsService struct {
+	settingsRepo repositories.DetectionSettingsRepository
+	cacheTTL     time.Duration
+
+	mu    sync.RWMutex
+	cache map[int]cachedDetectionSettings
+}
+
+// cachedDetectionSettings expire so an update made through another replica
+// is picked up within the TTL.
+type cachedDetectionSettings struct {
+	settings *entities.DetectionSettings
+	loadedAt time.Time
+}
+
+func NewDetectionSettingsService(settingsRepo repositories.DetectionSettingsRepository) DetectionSettingsService {
+	return &detectionSettingsService{
+		settingsRepo: settingsRepo,
+		cacheTTL:     settingsCacheTTL(),
+		cache:        make(map[int]cachedDetectionSettings),
+	}
+}
+
+// settingsCacheTTL is how long per-seller settings read on every frame are
+// kept in memory before they are loaded again.
+func settingsCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("SETTINGS_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return ttl
+}
+
+func (s *detectionSettingsService) GetSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[sellerID]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < s.cacheTTL {
+		return cached.settings, nil
+	}
+
+	settings, err := s.settingsRepo.FindSettings(ctx, sellerID)
+	if errors.Is(err, entities.ErrNotFound) {
+		settings, err = entities.DefaultDetectionSettings(sellerID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.remember(sellerID, settings)
+	return settings, nil
+}
+
+func (s *detectionSettingsService) UpdateSettings(ctx context.Context, sellerID int, req *entities.DetectionSettingsRequest) (*entities.DetectionSettings, error) {
+	if roi := req.ROI; roi != nil && (roi.X+roi.Width > 1 || roi.Y+roi.Height > 1) {
+		return nil, fmt.Errorf("%w: roi must lie within the frame", entities.ErrInvalidInput)
+	}
+
+	settings := &entities.DetectionSettings{
+		SellerID:            sellerID,
+		SimilarityThreshold: req.SimilarityThreshold,
+		MinConfidence:       req.MinConfidence,
+		IgnoredClasses:      normalizeClasses(req.IgnoredClasses),
+		ROI:                 req.ROI,
+	}
+	if err := s.settingsRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	s.remember(sellerID, settings)
+	return settings, nil
+}
+
+func (s *detectionSettingsService) ResetSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error) {
+	if err := s.settingsRepo.DeleteSettings(ctx, sellerID); err != nil {
+		return nil, err
+	}
+
+	settings := entities.DefaultDetectionSettings(sellerID)
+	s.remember(sellerID, settings)
+	return settings, nil
+}
+
+func (s *detectionSettingsService) remember(sellerID int, settings *entities.DetectionSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[sellerID] = cachedDetectionSettings{settings: settings, loadedAt: time.Now()}
+}
+
+// settingsForPrediction loads the seller's settings for a prediction. A
+// prediction made with the ML service's defaults beats none at all, so a
+// failure to load them is only logged.
+func settingsForPrediction(ctx context.Context, service DetectionSettingsService, sellerID int) *entities.DetectionSettings {
+	settings, err := service.GetSettings(ctx, sellerID)
+	if err != nil {
+		log.Printf("Failed to load detection settings of seller %d: %v", sellerID, err)
+		return nil
+	}
+	return settings
+}
+
+// normalizeClasses lower-cases class names the way YOLO reports them and
+// drops duplicates.
+func normalizeClasses(classes []string) []string {
+	normalized := make([]string, 0, len(classes))
+	seen := make(map[string]bool)
+	for _, class := range classes {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if class == "" || seen[class] {
+			continue
+		}
+		seen[class] = true
+		normalized = append(normalized, class)
+	}
+	return normalized
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// frameIngest runs detection on the frames a publisher sends over its
+// signaling connection and pushes each result back on it. Only the newest
+// frame waits for detection, so while the ML service lags the frames it
+// cannot get to are dropped instead of piling up behind it.
+type frameIngest struct {
+	detect   frameDetector
+	client   *entities.Client
+	sellerID int
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	pending *pendingFrame
+	dropped int
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// pendingFrame is a frame waiting for detection. Its age is measured from
+// when it arrived, since its capture time comes from the publisher's clock.
+type pendingFrame struct {
+	*entities.FrameMessage
+	receivedAt time.Time
+}
+
+func newFrameIngest(detect frameDetector, client *entities.Client, sellerID int, maxAge time.Duration) *frameIngest {
+	ctx, cancel := context.WithCancel(context.Background())
+	ingest := &frameIngest{
+		detect:   detect,
+		client:   client,
+		sellerID: sellerID,
+		maxAge:   maxAge,
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go ingest.run()
+	return ingest
+}
+
+// push queues a frame for detection, replacing the one waiting if detection
+// has not got to it yet.
+func (i *frameIngest) push(frame *entities.FrameMessage) {
+	i.mu.Lock()
+	if i.pending != nil {
+		i.dropped++
+	}
+	i.pending = &pendingFrame{FrameMessage: frame, receivedAt: time.Now()}
+	i.mu.Unlock()
+
+	select {
+	case i.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close stops detection, abandoning the frame in flight.
+func (i *frameIngest) close() {
+	i.cancel()
+}
+
+func (i *frameIngest) run() {
+	for {
+		select {
+		case <-i.ctx.Done():
+			return
+		case <-i.wake:
+		}
+
+		frame, dropped := i.take()
+		if frame == nil {
+			continue
+		}
+
+		result, err := i.detect(i.ctx, i.sellerID, frame.JPEG, frame.CapturedAt)
+		if i.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			i.client.WriteJSON(entities.WebRTCMessage{
+				Type: entities.MessageTypeError,
+				Room: i.client.RoomID,
+				Data: entities.ErrorPayload{
+					Code:        entities.SignalingErrDetectionFailed,
+					Message:     err.Error(),
+					MessageID:   strconv.FormatUint(uint64(frame.Sequence), 10),
+					MessageType: entities.MessageTypeFrame,
+				},
+			})
+			continue
+		}
+
+		sequence := frame.Sequence
+		i.client.WriteJSON(entities.WebRTCMessage{
+			Type: entities.MessageTypeDetectionResult,
+			Data: entities.DetectionResultPayload{
+				Source:     "socket",
+				CapturedAt: frame.CapturedAt,
+				Result:     result,
+				Sequence:   &sequence,
+				Dropped:    dropped,
+			},
+			Room: i.client.RoomID,
+			To:   i.client.ID,
+		})
+	}
+}
+
+// take removes the waiting frame, skipping it if it has gone stale, and
+// returns it with the number of frames dropped since the last one taken.
+func (i *frameIngest) take() (*pendingFrame, int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	frame := i.pending
+	i.pending = nil
+	if frame == nil {
+		return nil, 0
+	}
+	if time.Since(frame.receivedAt) > i.maxAge {
+		i.dropped++
+		return nil, 0
+	}
+
+	dropped := i.dropped
+	i.dropped = 0
+	return frame, dropped
+}
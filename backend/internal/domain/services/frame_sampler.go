@@ -0,0 +1,413 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media/samplebuilder"
+	"golang.org/x/image/vp8"
+)
+
+const (
+	// samplerClientID is the ID the frame sampler joins rooms with. Clients
+	// joining over WebSocket may not use it.
+	samplerClientID = "frame-sampler"
+	// samplerConnectTimeout bounds how long the sampler waits for the
+	// publisher to answer and send video before trying again.
+	samplerConnectTimeout = 15 * time.Second
+	samplerRetryDelay     = 10 * time.Second
+	samplerJPEGQuality    = 80
+	// samplerMaxLatePackets is how many packets the sample builder holds
+	// while waiting for a missing one before giving the frame up.
+	samplerMaxLatePackets = 128
+)
+
+// frameSampler pulls the publisher's video into the server over WebRTC and
+// runs product detection on keyframes taken from it, so detection does not
+// depend on frames uploaded by the seller's browser.
+type frameSampler struct {
+	repo     repositories.WebRTCRepository
+	detect   frameDetector
+	newPeer  func(role string) (*webrtc.PeerConnection, error)
+	interval time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*samplerSession
+}
+
+// frameDetector runs detection on one JPEG frame of a seller's stream,
+// captured at capturedAt.
+type frameDetector func(ctx context.Context, sellerID int, frame []byte, capturedAt time.Time) (*entities.PredictionResponse, error)
+
+// samplerSession is the sampling running in one room.
+type samplerSession struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newFrameSampler returns nil unless FRAME_SAMPLING_ENABLED is set.
+func newFrameSampler(
+	repo repositories.WebRTCRepository,
+	detect frameDetector,
+	newPeer func(role string) (*webrtc.PeerConnection, error),
+) *frameSampler {
+	if enabled := strings.ToLower(os.Getenv("FRAME_SAMPLING_ENABLED")); enabled != "true" && enabled != "1" {
+		return nil
+	}
+	interval, err := time.ParseDuration(os.Getenv("FRAME_SAMPLE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	return &frameSampler{
+		repo:     repo,
+		detect:   detect,
+		newPeer:  newPeer,
+		interval: interval,
+		sessions: make(map[string]*samplerSession),
+	}
+}
+
+// start begins sampling the publisher of a seller room, replacing any
+// sampling already running in it.
+func (f *frameSampler) start(roomID, publisherID string) {
+	sellerID, ok := entities.SellerIDFromRoom(roomID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &samplerSession{cancel: cancel, done: make(chan struct{})}
+
+	f.mu.Lock()
+	previous := f.sessions[roomID]
+	f.sessions[roomID] = session
+	f.mu.Unlock()
+
+	go func() {
+		defer close(session.done)
+		// Both sessions join the room under the same client ID, so the
+		// previous one has to leave first.
+		if previous != nil {
+			previous.cancel()
+			<-previous.done
+		}
+		f.run(ctx, roomID, publisherID, sellerID)
+	}()
+}
+
+func (f *frameSampler) stop(roomID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if session, exists := f.sessions[roomID]; exists {
+		session.cancel()
+		delete(f.sessions, roomID)
+	}
+}
+
+// run keeps a connection to the publisher until sampling is stopped. The
+// publisher may not be sending video yet when it joins, so failed attempts
+// are retried.
+func (f *frameSampler) run(ctx context.Context, roomID, publisherID string, sellerID int) {
+	for {
+		err := f.connect(ctx, roomID, publisherID, sellerID)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Frame sampler for room %s: %v; retrying in %s", roomID, err, samplerRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(samplerRetryDelay):
+		}
+	}
+}
+
+// connect joins the room as a receive-only peer of the publisher and samples
+// its video until the connection drops or ctx is cancelled.
+func (f *frameSampler) connect(ctx context.Context, roomID, publisherID string, sellerID int) error {
+	pc, err := f.newPeer(entities.RoleSampler)
+	if err != nil {
+		return fmt.Errorf("failed to create peer connection: %w", err)
+	}
+	defer pc.Close()
+
+	// Signaling messages from the publisher arrive through the room like
+	// any other client's; only the latest few matter.
+	inbox := make(chan entities.WebRTCMessage, 16)
+	client := &entities.Client{
+		ID:     samplerClientID,
+		Role:   entities.RoleSampler,
+		RoomID: roomID,
+		Deliver: func(v interface{}) error {
+			if msg, ok := v.(entities.WebRTCMessage); ok && msg.From == publisherID {
+				select {
+				case inbox <- msg:
+				default:
+				}
+			}
+			return nil
+		},
+		ConnectedAt: time.Now(),
+	}
+	f.repo.AddClientToRoom(roomID, client)
+	defer f.repo.RemoveClientFromRoom(roomID, samplerClientID)
+
+	for _, kind := range []webrtc.RTPCodecType{webrtc.RTPCodecTypeVideo, webrtc.RTPCodecTypeAudio} {
+		if _, err := pc.AddTransceiverFromKind(kind, webrtc.RTPTransceiverInit{Direction: webrtc.RTPTransceiverDirectionRecvonly}); err != nil {
+			return fmt.Errorf("failed to add %s transceiver: %w", kind, err)
+		}
+	}
+
+	tracks := make(chan *webrtc.TrackRemote, 1)
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if track.Kind() != webrtc.RTPCodecTypeVideo {
+			// Audio is negotiated so the publisher's offer-answer matches its
+			// stream, but it is never used.
+			go discardTrack(track)
+			return
+		}
+		select {
+		case tracks <- track:
+		default:
+		}
+	})
+
+	failed := make(chan struct{})
+	var failOnce sync.Once
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateDisconnected, webrtc.PeerConnectionStateClosed:
+			failOnce.Do(func() { close(failed) })
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create offer: %w", err)
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		return fmt.Errorf("failed to set local description: %w", err)
+	}
+
+	timeout := time.NewTimer(samplerConnectTimeout)
+	defer timeout.Stop()
+
+	// Candidates are sent inside the offer rather than trickled.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout.C:
+		return errors.New("timed out gathering ICE candidates")
+	case <-gathered:
+	}
+
+	if err := f.repo.SendToClient(roomID, publisherID, entities.WebRTCMessage{
+		Type: entities.MessageTypeWebRTCOffer,
+		Data: entities.SessionDescriptionPayload{Type: webrtc.SDPTypeOffer.String(), SDP: pc.LocalDescription().SDP},
+		Room: roomID,
+		From: samplerClientID,
+		To:   publisherID,
+	}); err != nil {
+		return fmt.Errorf("failed to send offer: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	// Closing the connection ends the sampling goroutines' reads, so it has
+	// to happen before waiting for them.
+	defer pc.Close()
+
+	waitForVideo := timeout.C
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-failed:
+			return errors.New("connection to publisher lost")
+		case <-waitForVideo:
+			return errors.New("publisher did not send video")
+		case track := <-tracks:
+			waitForVideo = nil
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f.sample(pc, track, roomID, publisherID, sellerID)
+			}()
+		case msg := <-inbox:
+			if err := handleSamplerSignal(pc, msg); err != nil {
+				log.Printf("Frame sampler for room %s: %v", roomID, err)
+			}
+		}
+	}
+}
+
+// handleSamplerSignal applies the publisher's answer and ICE candidates.
+func handleSamplerSignal(pc *webrtc.PeerConnection, msg entities.WebRTCMessage) error {
+	switch msg.Type {
+	case entities.MessageTypeWebRTCAnswer:
+		payload, ok := msg.Data.(entities.SessionDescriptionPayload)
+		if !ok {
+			return errors.New("unexpected answer payload")
+		}
+		return pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: payload.SDP})
+
+	case entities.MessageTypeWebRTCICECandidate:
+		payload, ok := msg.Data.(entities.ICECandidatePayload)
+		if !ok {
+			return errors.New("unexpected ice candidate payload")
+		}
+		return pc.AddICECandidate(webrtc.ICECandidateInit{
+			Candidate:        payload.Candidate,
+			SDPMid:           payload.SDPMid,
+			SDPMLineIndex:    payload.SDPMLineIndex,
+			UsernameFragment: payload.UsernameFragment,
+		})
+	}
+	return nil
+}
+
+// sample reassembles the publisher's VP8 frames and hands a keyframe to
+// detection once per interval. A keyframe is requested every interval so
+// one is always close by.
+func (f *frameSampler) sample(pc *webrtc.PeerConnection, track *webrtc.TrackRemote, roomID, publisherID string, sellerID int) {
+	if !strings.EqualFold(track.Codec().MimeType, webrtc.MimeTypeVP8) {
+		log.Printf("Frame sampler for room %s: unsupported codec %s", roomID, track.Codec().MimeType)
+		discardTrack(track)
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			pc.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: uint32(track.SSRC())}})
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Detection runs on its own goroutine with a single pending frame, so a
+	// slow ML service drops frames instead of stalling the track.
+	frames := make(chan []byte, 1)
+	defer close(frames)
+	go f.detectFrames(frames, roomID, publisherID, sellerID)
+
+	builder := samplebuilder.New(samplerMaxLatePackets, &codecs.VP8Packet{}, track.Codec().ClockRate)
+	decoder := vp8.NewDecoder()
+	var lastSampled time.Time
+
+	for {
+		packet, _, err := track.ReadRTP()
+		if err != nil {
+			return
+		}
+		builder.Push(packet)
+
+		for sample := builder.Pop(); sample != nil; sample = builder.Pop() {
+			if time.Since(lastSampled) < f.interval || !isVP8Keyframe(sample.Data) {
+				continue
+			}
+			frame, err := decodeVP8Keyframe(decoder, sample.Data)
+			if err != nil {
+				log.Printf("Frame sampler for room %s: %v", roomID, err)
+				continue
+			}
+			lastSampled = time.Now()
+
+			select {
+			case frames <- frame:
+			default:
+			}
+		}
+	}
+}
+
+func (f *frameSampler) detectFrames(frames <-chan []byte, roomID, publisherID string, sellerID int) {
+	// While the ML service is down every frame fails fast; log the outage
+	// once rather than once per frame.
+	unavailable := false
+	for frame := range frames {
+		capturedAt := time.Now()
+		result, err := f.detect(context.Background(), sellerID, frame, capturedAt)
+		if errors.Is(err, entities.ErrMLUnavailable) {
+			if !unavailable {
+				log.Printf("Frame sampler for room %s: skipping frames: %v", roomID, err)
+			}
+			unavailable = true
+			continue
+		}
+		if err != nil {
+			log.Printf("Frame sampler for room %s: detection failed: %v", roomID, err)
+			continue
+		}
+		unavailable = false
+
+		f.repo.SendToClient(roomID, publisherID, entities.WebRTCMessage{
+			Type: entities.MessageTypeDetectionResult,
+			Data: entities.DetectionResultPayload{
+				Source:     "server",
+				CapturedAt: capturedAt,
+				Result:     result,
+			},
+			Room: roomID,
+			From: samplerClientID,
+			To:   publisherID,
+		})
+	}
+}
+
+// isVP8Keyframe checks the frame tag: the low bit of the first byte is 0 for
+// keyframes.
+func isVP8Keyframe(data []byte) bool {
+	return len(data) > 0 && data[0]&0x01 == 0
+}
+
+// decodeVP8Keyframe decodes a VP8 keyframe and re-encodes it as a JPEG, the
+// format the ML service expects.
+func decodeVP8Keyframe(decoder *vp8.Decoder, data []byte) ([]byte, error) {
+	decoder.Init(bytes.NewReader(data), len(data))
+	if _, err := decoder.DecodeFrameHeader(); err != nil {
+		return nil, fmt.Errorf("failed to decode frame header: %w", err)
+	}
+	img, err := decoder.DecodeFrame()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode frame: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: samplerJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode frame: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func discardTrack(track *webrtc.TrackRemote) {
+	buf := make([]byte, 1500)
+	for {
+		if _, _, err := track.Read(buf); err != nil {
+			return
+		}
+	}
+}
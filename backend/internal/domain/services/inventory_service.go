@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"os"
+	"time"
+)
+
+type InventoryService interface {
+	GetStock(ctx context.Context, productID int) (*entities.StockLevel, error)
+	SetStock(ctx context.Context, sellerID int, productID int, stock int) (*entities.StockLevel, error)
+	SetVariantStock(ctx context.Context, sellerID int, productID int, variantID int, stock int) (*entities.StockLevel, error)
+	// ReservationTTL is how long stock stays reserved for a cart item.
+	ReservationTTL() time.Duration
+	// PublishStock broadcasts the current stock of the given products to
+	// their sellers' live rooms.
+	PublishStock(ctx context.Context, productIDs ...int)
+	// RunReservationSweeper releases expired reservations periodically until
+	// ctx is cancelled.
+	RunReservationSweeper(ctx context.Context)
+}
+
+type inventoryService struct {
+	stockRepo      repositories.StockRepository
+	productRepo    repositories.ProductRepository
+	variantRepo    repositories.VariantRepository
+	webrtcRepo     repositories.WebRTCRepository
+	reservationTTL time.Duration
+	sweepInterval  time.Duration
+}
+
+func NewInventoryService(
+	stockRepo repositories.StockRepository,
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.VariantRepository,
+	webrtcRepo repositories.WebRTCRepository,
+) InventoryService {
+	reservationTTL, err := time.ParseDuration(os.Getenv("STOCK_RESERVATION_TTL"))
+	if err != nil || reservationTTL <= 0 {
+		reservationTTL = 10 * time.Minute
+	}
+	sweepInterval, err := time.ParseDuration(os.Getenv("STOCK_SWEEP_INTERVAL"))
+	if err != nil || sweepInterval <= 0 {
+		sweepInterval = 30 * time.Second
+	}
+
+	return &inventoryService{
+		stockRepo:      stockRepo,
+		productRepo:    productRepo,
+		variantRepo:    variantRepo,
+		webrtcRepo:     webrtcRepo,
+		reservationTTL: reservationTTL,
+		sweepInterval:  sweepInterval,
+	}
+}
+
+func (s *inventoryService) GetStock(ctx context.Context, productID int) (*entities.StockLevel, error) {
+	return s.findLevel(ctx, productID, nil)
+}
+
+func (s *inventoryService) SetStock(ctx context.Context, sellerID int, productID int, stock int) (*entities.StockLevel, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("product %d belongs to another seller: %w", productID, entities.ErrForbidden)
+	}
+
+	// The stock of a product with variants is the sum of theirs, so a
+	// product-level figure would never be seen.
+	variants, err := s.variantRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) > 0 {
+		return nil, fmt.Errorf("%w: product %d has variants; set the stock of each variant instead", entities.ErrInvalidInput, productID)
+	}
+
+	if err := s.stockRepo.SetStock(ctx, productID, stock); err != nil {
+		return nil, err
+	}
+
+	level, err := s.GetStock(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	s.broadcast(*level)
+	return level, nil
+}
+
+func (s *inventoryService) SetVariantStock(ctx context.Context, sellerID int, productID int, variantID int, stock int) (*entities.StockLevel, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("product %d belongs to another seller: %w", productID, entities.ErrForbidden)
+	}
+
+	variant, err := s.variantRepo.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != productID {
+		return nil, fmt.Errorf("variant %d of product %d: %w", variantID, productID, entities.ErrNotFound)
+	}
+
+	if err := s.stockRepo.SetVariantStock(ctx, variantID, stock); err != nil {
+		return nil, err
+	}
+
+	level, err := s.findLevel(ctx, productID, &variantID)
+	if err != nil {
+		return nil, err
+	}
+	s.PublishStock(ctx, productID)
+	return level, nil
+}
+
+// findLevel picks the product-level entry, or the given variant's entry,
+// out of a product's stock levels.
+func (s *inventoryService) findLevel(ctx context.Context, productID int, variantID *int) (*entities.StockLevel, error) {
+	levels, err := s.stockRepo.FindLevels(ctx, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range levels {
+		level := &levels[i]
+		if variantID == nil && level.VariantID == nil {
+			return level, nil
+		}
+		if variantID != nil && level.VariantID != nil && *level.VariantID == *variantID {
+			return level, nil
+		}
+	}
+	return nil, entities.ErrNotFound
+}
+
+func (s *inventoryService) ReservationTTL() time.Duration {
+	return s.reservationTTL
+}
+
+func (s *inventoryService) PublishStock(ctx context.Context, productIDs ...int) {
+	if len(productIDs) == 0 {
+		return
+	}
+
+	levels, err := s.stockRepo.FindLevels(ctx, productIDs)
+	if err != nil {
+		log.Printf("Failed to load stock levels for broadcast: %v", err)
+		return
+	}
+	for _, level := range levels {
+		s.broadcast(level)
+	}
+}
+
+func (s *inventoryService) broadcast(level entities.StockLevel) {
+	roomID := entities.SellerRoomID(level.SellerID)
+	message := entities.WebRTCMessage{
+		Type: entities.MessageTypeStockUpdated,
+		Data: entities.StockUpdatedPayload{
+			ProductID: level.ProductID,
+			VariantID: level.VariantID,
+			Stock:     level.Stock,
+			Available: level.Available,
+		},
+		Room: roomID,
+	}
+	s.webrtcRepo.BroadcastToRoom(roomID, message, "")
+}
+
+func (s *inventoryService) RunReservationSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			productIDs, err := s.stockRepo.ReleaseExpired(ctx)
+			if err != nil {
+				log.Printf("Failed to release expired stock reservations: %v", err)
+				continue
+			}
+			if len(productIDs) > 0 {
+				log.Printf("Released expired stock reservations for %d products", len(productIDs))
+				s.PublishStock(ctx, productIDs...)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
@@ -9,19 +10,35 @@ import (
 
 type LiveStreamService interface {
 	StartLiveStream(req *entities.LiveStreamRequest) (*entities.LiveStream, error)
-	EndLiveStream(sellerID string) error
-	GetActiveLiveStreams() ([]entities.LiveStream, error)
-	GetLiveStreamBySellerID(sellerID string) (*entities.LiveStream, error)
-	UpdateViewerCount(sellerID string, count int) error
+	EndLiveStream(sellerID int) error
+	ListLiveStreams(filter *entities.LiveStreamFilter) (*entities.LiveStreamPage, error)
+	GetLiveStreamBySellerID(sellerID int) (*entities.LiveStream, error)
+	UpdateViewerCount(sellerID int, count int) error
+	// GetTimeline returns when each product was on camera during a stream.
+	GetTimeline(ctx context.Context, liveStreamID int, params *entities.TimelineParams) (*entities.LiveStreamTimeline, error)
 }
 
 type liveStreamService struct {
-	repo repositories.LiveStreamRepository
+	repo           repositories.LiveStreamRepository
+	sellerRepo     repositories.SellerRepository
+	detectionRepo  repositories.DetectionRepository
+	productRepo    repositories.ProductRepository
+	retrainService RetrainService
 }
 
-func NewLiveStreamService(repo repositories.LiveStreamRepository) LiveStreamService {
+func NewLiveStreamService(
+	repo repositories.LiveStreamRepository,
+	sellerRepo repositories.SellerRepository,
+	detectionRepo repositories.DetectionRepository,
+	productRepo repositories.ProductRepository,
+	retrainService RetrainService,
+) LiveStreamService {
 	return &liveStreamService{
-		repo: repo,
+		repo:           repo,
+		sellerRepo:     sellerRepo,
+		detectionRepo:  detectionRepo,
+		productRepo:    productRepo,
+		retrainService: retrainService,
 	}
 }
 
@@ -32,6 +49,14 @@ func (s *liveStreamService) StartLiveStream(req *entities.LiveStreamRequest) (*e
 		return nil, fmt.Errorf("seller already has an active livestream")
 	}
 
+	if req.SellerName == "" {
+		seller, err := s.sellerRepo.FindByID(context.Background(), req.SellerID)
+		if err != nil {
+			return nil, err
+		}
+		req.SellerName = seller.ShopName
+	}
+
 	stream := &entities.LiveStream{
 		SellerID:    req.SellerID,
 		SellerName:  req.SellerName,
@@ -47,10 +72,14 @@ func (s *liveStreamService) StartLiveStream(req *entities.LiveStreamRequest) (*e
 		return nil, err
 	}
 
+	// Catalogue changes still waiting out their debounce are trained now,
+	// so the stream recognises them as soon as possible.
+	s.retrainService.RetrainIfDirty(context.Background(), stream.SellerID)
+
 	return stream, nil
 }
 
-func (s *liveStreamService) EndLiveStream(sellerID string) error {
+func (s *liveStreamService) EndLiveStream(sellerID int) error {
 	err := s.repo.EndLiveStream(sellerID)
 	if err != nil {
 		return err
@@ -59,16 +88,16 @@ func (s *liveStreamService) EndLiveStream(sellerID string) error {
 	return nil
 }
 
-func (s *liveStreamService) GetActiveLiveStreams() ([]entities.LiveStream, error) {
-	streams, err := s.repo.GetActiveLiveStreams()
+func (s *liveStreamService) ListLiveStreams(filter *entities.LiveStreamFilter) (*entities.LiveStreamPage, error) {
+	page, err := s.repo.ListLiveStreams(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return streams, nil
+	return page, nil
 }
 
-func (s *liveStreamService) GetLiveStreamBySellerID(sellerID string) (*entities.LiveStream, error) {
+func (s *liveStreamService) GetLiveStreamBySellerID(sellerID int) (*entities.LiveStream, error) {
 	stream, err := s.repo.GetLiveStreamBySellerID(sellerID)
 	if err != nil {
 		return nil, err
@@ -77,11 +106,68 @@ func (s *liveStreamService) GetLiveStreamBySellerID(sellerID string) (*entities.
 	return stream, nil
 }
 
-func (s *liveStreamService) UpdateViewerCount(sellerID string, count int) error {
+func (s *liveStreamService) UpdateViewerCount(sellerID int, count int) error {
 	err := s.repo.UpdateViewerCount(sellerID, count)
 	if err != nil {
 		return err
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *liveStreamService) GetTimeline(ctx context.Context, liveStreamID int, params *entities.TimelineParams) (*entities.LiveStreamTimeline, error) {
+	stream, err := s.repo.GetLiveStreamByID(liveStreamID)
+	if err != nil {
+		return nil, err
+	}
+
+	gap := params.Gap
+	if gap == 0 {
+		gap = entities.DefaultTimelineGapSeconds
+	}
+	appearances, err := s.detectionRepo.FindAppearances(ctx, liveStreamID, gap, params.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &entities.LiveStreamTimeline{
+		LiveStreamID: stream.ID,
+		StartedAt:    stream.StartedAt,
+		EndedAt:      stream.EndedAt,
+		Products:     []entities.ProductTimeline{},
+	}
+
+	// Appearances come ordered by product, so each product's run is
+	// contiguous.
+	var productIDs []int
+	for _, appearance := range appearances {
+		appearance.StartOffset = appearance.StartedAt.Sub(stream.StartedAt).Seconds()
+		appearance.EndOffset = appearance.EndedAt.Sub(stream.StartedAt).Seconds()
+
+		last := len(timeline.Products) - 1
+		if last < 0 || timeline.Products[last].ProductID != appearance.ProductID {
+			timeline.Products = append(timeline.Products, entities.ProductTimeline{ProductID: appearance.ProductID})
+			productIDs = append(productIDs, appearance.ProductID)
+			last++
+		}
+		product := &timeline.Products[last]
+		product.Appearances = append(product.Appearances, appearance)
+		product.OnCameraSeconds += appearance.EndOffset - appearance.StartOffset
+	}
+
+	if len(productIDs) > 0 {
+		products, err := s.productRepo.FindByIDs(ctx, productIDs)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[int]string, len(products))
+		for _, product := range products {
+			names[product.ID] = product.Name
+		}
+		for i := range timeline.Products {
+			timeline.Products[i].ProductName = names[timeline.Products[i].ProductID]
+		}
+	}
+
+	return timeline, nil
+}
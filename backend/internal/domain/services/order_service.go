@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+)
+
+type OrderService interface {
+	GetOrder(ctx context.Context, principal *entities.Principal, id int) (*entities.Order, error)
+	ListBuyerOrders(ctx context.Context, principal *entities.Principal) ([]entities.Order, error)
+	ListSellerOrders(ctx context.Context, principal *entities.Principal, status string) ([]entities.Order, error)
+	TransitionOrder(ctx context.Context, principal *entities.Principal, id int, req *entities.OrderTransitionRequest) (*entities.Order, error)
+	CancelOrder(ctx context.Context, principal *entities.Principal, id int, note string) (*entities.Order, error)
+}
+
+type orderService struct {
+	orderRepo        repositories.OrderRepository
+	paymentRepo      repositories.PaymentRepository
+	inventoryService InventoryService
+}
+
+func NewOrderService(orderRepo repositories.OrderRepository, paymentRepo repositories.PaymentRepository, inventoryService InventoryService) OrderService {
+	return &orderService{
+		orderRepo:        orderRepo,
+		paymentRepo:      paymentRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+// GetOrder is available to the buyer who placed the order and to the seller
+// fulfilling it.
+func (s *orderService) GetOrder(ctx context.Context, principal *entities.Principal, id int) (*entities.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != principal.UserID && (!principal.IsSeller() || order.SellerID != principal.SellerID) {
+		return nil, entities.ErrNotFound
+	}
+	return order, nil
+}
+
+func (s *orderService) ListBuyerOrders(ctx context.Context, principal *entities.Principal) ([]entities.Order, error) {
+	return s.orderRepo.FindByUserID(ctx, principal.UserID)
+}
+
+func (s *orderService) ListSellerOrders(ctx context.Context, principal *entities.Principal, status string) ([]entities.Order, error) {
+	if status != "" && !entities.IsValidOrderStatus(status) {
+		return nil, fmt.Errorf("%w: unknown order status %q", entities.ErrInvalidInput, status)
+	}
+	return s.orderRepo.FindBySellerID(ctx, principal.SellerID, status)
+}
+
+// TransitionOrder advances a paid order through fulfilment on behalf of the
+// seller that owns it. Refunds go through PaymentService.RefundOrder.
+func (s *orderService) TransitionOrder(ctx context.Context, principal *entities.Principal, id int, req *entities.OrderTransitionRequest) (*entities.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.IsSeller() || order.SellerID != principal.SellerID {
+		return nil, fmt.Errorf("order %d belongs to another seller: %w", id, entities.ErrForbidden)
+	}
+	if entities.IsValidOrderStatus(req.Status) && !entities.IsSellerOrderStatus(req.Status) {
+		return nil, fmt.Errorf("%w: orders cannot be moved to %s by hand", entities.ErrInvalidInput, req.Status)
+	}
+
+	return s.transition(ctx, principal, order, req.Status, req.Note)
+}
+
+// CancelOrder lets the buyer withdraw an order that has not been paid yet. An
+// order whose payment is still in flight cannot be cancelled until the
+// provider reports the outcome.
+func (s *orderService) CancelOrder(ctx context.Context, principal *entities.Principal, id int, note string) (*entities.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != principal.UserID {
+		return nil, entities.ErrNotFound
+	}
+	if order.Status != entities.OrderStatusPending {
+		return nil, fmt.Errorf("%w: only pending orders can be cancelled by the buyer", entities.ErrConflict)
+	}
+
+	payment, err := s.paymentRepo.FindLatestByOrderID(ctx, id)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, err
+	}
+	if payment != nil && (payment.Status == entities.PaymentStatusPending || payment.Status == entities.PaymentStatusAuthorized) {
+		return nil, fmt.Errorf("%w: payment %s is still %s", entities.ErrConflict, payment.ProviderPaymentID, payment.Status)
+	}
+
+	return s.transition(ctx, principal, order, entities.OrderStatusCancelled, note)
+}
+
+func (s *orderService) transition(ctx context.Context, principal *entities.Principal, order *entities.Order, to string, note string) (*entities.Order, error) {
+	if !entities.IsValidOrderStatus(to) {
+		return nil, fmt.Errorf("%w: unknown order status %q", entities.ErrInvalidInput, to)
+	}
+	if !entities.CanTransitionOrder(order.Status, to) {
+		return nil, fmt.Errorf("%w: cannot move order from %s to %s", entities.ErrConflict, order.Status, to)
+	}
+
+	actor := principal.UserID
+	event := &entities.OrderEvent{
+		OrderID:     order.ID,
+		FromStatus:  order.Status,
+		ToStatus:    to,
+		ActorUserID: &actor,
+		Note:        note,
+	}
+	if err := s.orderRepo.UpdateStatus(ctx, event); err != nil {
+		return nil, err
+	}
+	if entities.OrderTransitionRestocks(event.FromStatus, event.ToStatus) {
+		s.inventoryService.PublishStock(ctx, orderProductIDs(order)...)
+	}
+
+	return s.orderRepo.FindByID(ctx, order.ID)
+}
+
+func orderProductIDs(order *entities.Order) []int {
+	var productIDs []int
+	for _, item := range order.Items {
+		if item.ProductID != nil {
+			productIDs = append(productIDs, *item.ProductID)
+		}
+	}
+	return productIDs
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"net/http"
+	"os"
+)
+
+type PaymentService interface {
+	PayOrder(ctx context.Context, principal *entities.Principal, orderID int) (*entities.Payment, error)
+	RefundOrder(ctx context.Context, principal *entities.Principal, orderID int) (*entities.Order, error)
+	// HandleWebhook verifies and applies a provider notification. It reports
+	// false when the event had already been processed.
+	HandleWebhook(ctx context.Context, provider string, payload []byte, header http.Header) (bool, error)
+}
+
+type paymentService struct {
+	paymentRepo      repositories.PaymentRepository
+	orderRepo        repositories.OrderRepository
+	inventoryService InventoryService
+	providers        map[string]repositories.PaymentProvider
+	defaultProvider  repositories.PaymentProvider
+	currency         string
+}
+
+// NewPaymentService uses the first provider for new payments; the others are
+// kept so webhooks and refunds for their earlier payments still work.
+func NewPaymentService(
+	paymentRepo repositories.PaymentRepository,
+	orderRepo repositories.OrderRepository,
+	inventoryService InventoryService,
+	providers ...repositories.PaymentProvider,
+) PaymentService {
+	currency := os.Getenv("PAYMENT_CURRENCY")
+	if currency == "" {
+		currency = "USD"
+	}
+
+	byName := make(map[string]repositories.PaymentProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &paymentService{
+		paymentRepo:      paymentRepo,
+		orderRepo:        orderRepo,
+		inventoryService: inventoryService,
+		providers:        byName,
+		defaultProvider:  providers[0],
+		currency:         currency,
+	}
+}
+
+// PayOrder starts a payment for a pending order. Calling it again while an
+// earlier attempt is still open returns that attempt instead of charging
+// twice.
+func (s *paymentService) PayOrder(ctx context.Context, principal *entities.Principal, orderID int) (*entities.Payment, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != principal.UserID {
+		return nil, entities.ErrNotFound
+	}
+	if order.Status != entities.OrderStatusPending {
+		return nil, fmt.Errorf("%w: order is %s", entities.ErrConflict, order.Status)
+	}
+
+	existing, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil && (existing.Status == entities.PaymentStatusPending || existing.Status == entities.PaymentStatusAuthorized) {
+		return existing, nil
+	}
+
+	intent, err := s.defaultProvider.CreateIntent(ctx, &entities.PaymentIntentParams{
+		OrderID:     order.ID,
+		Amount:      order.TotalAmount,
+		Currency:    s.currency,
+		Description: fmt.Sprintf("Order #%d", order.ID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &entities.Payment{
+		OrderID:           order.ID,
+		Provider:          s.defaultProvider.Name(),
+		ProviderPaymentID: intent.ProviderPaymentID,
+		Amount:            order.TotalAmount,
+		Currency:          s.currency,
+		Status:            intent.Status,
+		ClientSecret:      intent.ClientSecret,
+		CheckoutURL:       intent.CheckoutURL,
+	}
+	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// RefundOrder refunds the captured payment of an order on behalf of the
+// seller that owns it and moves the order to refunded.
+func (s *paymentService) RefundOrder(ctx context.Context, principal *entities.Principal, orderID int) (*entities.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.IsSeller() || order.SellerID != principal.SellerID {
+		return nil, fmt.Errorf("order %d belongs to another seller: %w", orderID, entities.ErrForbidden)
+	}
+	if !entities.CanTransitionOrder(order.Status, entities.OrderStatusRefunded) {
+		return nil, fmt.Errorf("%w: cannot refund a %s order", entities.ErrConflict, order.Status)
+	}
+
+	payment, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != entities.PaymentStatusSucceeded {
+		return nil, fmt.Errorf("%w: payment is %s", entities.ErrConflict, payment.Status)
+	}
+
+	provider, ok := s.providers[payment.Provider]
+	if !ok {
+		return nil, fmt.Errorf("payment provider %q is not configured", payment.Provider)
+	}
+	if err := provider.Refund(ctx, payment.ProviderPaymentID, payment.Amount); err != nil {
+		return nil, err
+	}
+
+	// Recorded as a payment event so a later provider notification for the
+	// same refund is recognised as a duplicate of this one.
+	actor := principal.UserID
+	payload, _ := json.Marshal(map[string]interface{}{"order_id": order.ID, "amount": payment.Amount, "actor_user_id": actor})
+	event := &entities.PaymentWebhookEvent{
+		EventID:           "refund:" + payment.ProviderPaymentID,
+		Type:              entities.PaymentEventRefunded,
+		ProviderPaymentID: payment.ProviderPaymentID,
+		Payload:           payload,
+	}
+	update := &entities.PaymentUpdate{
+		PaymentID:     payment.ID,
+		PaymentStatus: entities.PaymentStatusRefunded,
+		OrderEvent: &entities.OrderEvent{
+			OrderID:     order.ID,
+			FromStatus:  order.Status,
+			ToStatus:    entities.OrderStatusRefunded,
+			ActorUserID: &actor,
+		},
+	}
+	if _, err := s.paymentRepo.ApplyEvent(ctx, payment.Provider, event, update); err != nil {
+		return nil, err
+	}
+	if entities.OrderTransitionRestocks(order.Status, entities.OrderStatusRefunded) {
+		s.inventoryService.PublishStock(ctx, orderProductIDs(order)...)
+	}
+
+	return s.orderRepo.FindByID(ctx, order.ID)
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, providerName string, payload []byte, header http.Header) (bool, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return false, fmt.Errorf("payment provider %q: %w", providerName, entities.ErrNotFound)
+	}
+
+	event, err := provider.VerifyWebhook(payload, header)
+	if err != nil {
+		return false, err
+	}
+
+	payment, err := s.paymentRepo.FindByProviderPaymentID(ctx, providerName, event.ProviderPaymentID)
+	if err != nil {
+		return false, err
+	}
+
+	update := &entities.PaymentUpdate{PaymentID: payment.ID, PaymentStatus: payment.Status}
+	var order *entities.Order
+
+	switch event.Type {
+	case entities.PaymentEventAuthorized, entities.PaymentEventSucceeded:
+		if payment.Status == entities.PaymentStatusSucceeded || payment.Status == entities.PaymentStatusRefunded {
+			break
+		}
+		order, err = s.orderRepo.FindByID(ctx, payment.OrderID)
+		if err != nil {
+			return false, err
+		}
+		if order.Status != entities.OrderStatusPending {
+			// The order was cancelled while the payment was in flight, so
+			// the money must not be kept: an authorisation is left
+			// uncaptured and a completed charge is refunded.
+			if event.Type == entities.PaymentEventAuthorized {
+				update.PaymentStatus = entities.PaymentStatusFailed
+				break
+			}
+			if err := provider.Refund(ctx, payment.ProviderPaymentID, payment.Amount); err != nil {
+				return false, err
+			}
+			update.PaymentStatus = entities.PaymentStatusRefunded
+			break
+		}
+		if event.Type == entities.PaymentEventAuthorized {
+			if err := provider.Capture(ctx, payment.ProviderPaymentID); err != nil {
+				return false, err
+			}
+		}
+		update.PaymentStatus = entities.PaymentStatusSucceeded
+		update.OrderEvent = &entities.OrderEvent{
+			OrderID:    payment.OrderID,
+			FromStatus: entities.OrderStatusPending,
+			ToStatus:   entities.OrderStatusPaid,
+			Note:       fmt.Sprintf("payment %s via %s", payment.ProviderPaymentID, providerName),
+		}
+
+	case entities.PaymentEventFailed:
+		if payment.Status == entities.PaymentStatusPending || payment.Status == entities.PaymentStatusAuthorized {
+			update.PaymentStatus = entities.PaymentStatusFailed
+		}
+
+	case entities.PaymentEventRefunded:
+		update.PaymentStatus = entities.PaymentStatusRefunded
+		order, err = s.orderRepo.FindByID(ctx, payment.OrderID)
+		if err != nil {
+			return false, err
+		}
+		if entities.CanTransitionOrder(order.Status, entities.OrderStatusRefunded) {
+			update.OrderEvent = &entities.OrderEvent{
+				OrderID:    order.ID,
+				FromStatus: order.Status,
+				ToStatus:   entities.OrderStatusRefunded,
+				Note:       fmt.Sprintf("refunded by %s", providerName),
+			}
+		}
+	}
+
+	applied, err := s.paymentRepo.ApplyEvent(ctx, providerName, event, update)
+	if err != nil {
+		return false, err
+	}
+	if applied && update.OrderEvent != nil && entities.OrderTransitionRestocks(update.OrderEvent.FromStatus, update.OrderEvent.ToStatus) {
+		s.inventoryService.PublishStock(ctx, orderProductIDs(order)...)
+	}
+	return applied, nil
+}
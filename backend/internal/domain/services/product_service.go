@@ -10,66 +10,133 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
-	"strconv"
+	"strings"
+	"unicode"
 )
 
 type ProductService interface {
-	GetProducts(ctx context.Context) ([]entities.Product, error)
+	ListProducts(ctx context.Context, filter *entities.ProductFilter) (*entities.ProductPage, error)
 	GetProduct(ctx context.Context, id int) (*entities.Product, error)
-	GetProductsBySellerID(ctx context.Context, sellerID int) ([]entities.Product, error)
+	// SearchProducts runs a full-text search over product names and
+	// descriptions. Every word of the query matches as a prefix, so partial
+	// input works for typeahead.
+	SearchProducts(ctx context.Context, params *entities.ProductSearchParams) (*entities.ProductSearchResponse, error)
 	CreateProduct(ctx context.Context, product *entities.Product, images []*multipart.FileHeader) error
-	UpdateProduct(ctx context.Context, product *entities.Product) error
-	DeleteProduct(ctx context.Context, id int) error
-	AddProductImages(ctx context.Context, productID int, images []*multipart.FileHeader) ([]entities.Image, error)
-	TrainProductModel(ctx context.Context, productID int) (*entities.TrainingResponse, error)
+	UpdateProduct(ctx context.Context, sellerID int, product *entities.Product) error
+	DeleteProduct(ctx context.Context, sellerID int, id int) error
+	AddProductImages(ctx context.Context, sellerID int, productID int, images []*multipart.FileHeader) ([]entities.Image, error)
+	// TrainProductModel queues a training job for the catalogue of the
+	// product's seller.
+	TrainProductModel(ctx context.Context, sellerID int, productID int) (*entities.TrainingJob, error)
 	PredictProduct(ctx context.Context, productID int, image *multipart.FileHeader) (*entities.PredictionResponse, error)
-	PinProduct(ctx context.Context, productID int, sellerID int, similarityScore float64) error
-	UnpinProduct(ctx context.Context, productID int, sellerID string) error
-	GetPinnedProducts(ctx context.Context, sellerID string) ([]entities.PinnedProduct, error)
-	UnpinAllProducts(ctx context.Context, sellerID string) (int64, error)
-	TrainAllSellers(ctx context.Context, sellerID string) (*entities.TrainingResponse, error)
-	GetTrainingStatus(ctx context.Context, sellerID string) (map[string]interface{}, error)
+	// PinProduct pins the product in the seller's live room, optionally
+	// highlighting one of its variants.
+	PinProduct(ctx context.Context, productID int, variantID *int, sellerID int, similarityScore float64) error
+	UnpinProduct(ctx context.Context, productID int, sellerID int) error
+	GetPinnedProducts(ctx context.Context, sellerID int) ([]entities.PinnedProduct, error)
+	UnpinAllProducts(ctx context.Context, sellerID int) (int64, error)
+	TrainAllSellers(ctx context.Context, sellerID int, fineTune bool) (*entities.TrainingJob, error)
+	GetTrainingStatus(ctx context.Context, sellerID int) (map[string]interface{}, error)
 }
 
 type productService struct {
 	productRepo      repositories.ProductRepository
+	variantRepo      repositories.VariantRepository
 	pinnedRepo       repositories.PinnedProductRepository
 	mlRepo           repositories.MLRepository
 	storageRepo      repositories.StorageRepository
+	trainingService  TrainingJobService
+	retrainService   RetrainService
+	settingsService  DetectionSettingsService
 	mlDatasetBaseDir string
 }
 
 func NewProductService(
 	productRepo repositories.ProductRepository,
+	variantRepo repositories.VariantRepository,
 	pinnedRepo repositories.PinnedProductRepository,
 	mlRepo repositories.MLRepository,
 	storageRepo repositories.StorageRepository,
+	trainingService TrainingJobService,
+	retrainService RetrainService,
+	settingsService DetectionSettingsService,
 ) ProductService {
 	return &productService{
 		productRepo:      productRepo,
+		variantRepo:      variantRepo,
 		pinnedRepo:       pinnedRepo,
 		mlRepo:           mlRepo,
 		storageRepo:      storageRepo,
+		trainingService:  trainingService,
+		retrainService:   retrainService,
+		settingsService:  settingsService,
 		mlDatasetBaseDir: "../ml_service/datasets",
 	}
 }
 
-func (s *productService) GetProducts(ctx context.Context) ([]entities.Product, error) {
-	return s.productRepo.FindAll(ctx)
+func (s *productService) ListProducts(ctx context.Context, filter *entities.ProductFilter) (*entities.ProductPage, error) {
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, fmt.Errorf("%w: min_price is greater than max_price", entities.ErrInvalidInput)
+	}
+	return s.productRepo.List(ctx, filter)
 }
 
-func (s *productService) GetProduct(ctx context.Context, id int) (*entities.Product, error) {
-	return s.productRepo.FindByID(ctx, id)
+func (s *productService) SearchProducts(ctx context.Context, params *entities.ProductSearchParams) (*entities.ProductSearchResponse, error) {
+	tsQuery := prefixTSQuery(params.Query)
+	if tsQuery == "" {
+		return nil, fmt.Errorf("%w: search query has no words", entities.ErrInvalidInput)
+	}
+
+	result, err := s.productRepo.Search(ctx, tsQuery, params)
+	if err != nil {
+		return nil, err
+	}
+	result.Query = params.Query
+	return result, nil
+}
+
+// maxSearchTerms caps how many words of a query are matched.
+const maxSearchTerms = 8
+
+// prefixTSQuery turns free text into a to_tsquery expression that requires
+// every word as a prefix, e.g. "red sne" becomes "red:* & sne:*". Anything
+// but letters and digits separates words, so user input cannot inject
+// tsquery operators.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + ":*"
+	}
+	return strings.Join(terms, " & ")
 }
 
-func (s *productService) GetProductsBySellerID(ctx context.Context, sellerID int) ([]entities.Product, error) {
-	return s.productRepo.FindBySellerID(ctx, sellerID)
+func (s *productService) GetProduct(ctx context.Context, id int) (*entities.Product, error) {
+	product, err := s.productRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Options, err = s.variantRepo.FindOptions(ctx, id); err != nil {
+		return nil, err
+	}
+	if product.Variants, err = s.variantRepo.FindByProductID(ctx, id); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
 func (s *productService) CreateProduct(ctx context.Context, product *entities.Product, images []*multipart.FileHeader) error {
 	if err := s.productRepo.Create(ctx, product); err != nil {
 		return err
 	}
+	s.retrainService.MarkChanged(ctx, product.SellerID)
 
 	if err := s.createMLDataset(product.ID, product.SellerID, product.Name); err != nil {
 		return fmt.Errorf("failed to create ML dataset: %w", err)
@@ -96,15 +163,37 @@ func (s *productService) CreateProduct(ctx context.Context, product *entities.Pr
 	return nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, product *entities.Product) error {
-	return s.productRepo.Update(ctx, product)
+func (s *productService) UpdateProduct(ctx context.Context, sellerID int, product *entities.Product) error {
+	existing, err := s.findOwnedProduct(ctx, sellerID, product.ID)
+	if err != nil {
+		return err
+	}
+	product.SellerID = existing.SellerID
+	product.CreatedAt = existing.CreatedAt
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return err
+	}
+	// Names and prices are part of the index too.
+	s.retrainService.MarkChanged(ctx, product.SellerID)
+	return nil
 }
 
-func (s *productService) DeleteProduct(ctx context.Context, id int) error {
-	return s.productRepo.Delete(ctx, id)
+func (s *productService) DeleteProduct(ctx context.Context, sellerID int, id int) error {
+	if _, err := s.findOwnedProduct(ctx, sellerID, id); err != nil {
+		return err
+	}
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.retrainService.MarkChanged(ctx, sellerID)
+	return nil
 }
 
-func (s *productService) AddProductImages(ctx context.Context, productID int, images []*multipart.FileHeader) ([]entities.Image, error) {
+func (s *productService) AddProductImages(ctx context.Context, sellerID int, productID int, images []*multipart.FileHeader) ([]entities.Image, error) {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return nil, err
+	}
+
 	var imageURLs []string
 	var addedImages []entities.Image
 
@@ -119,6 +208,7 @@ func (s *productService) AddProductImages(ctx context.Context, productID int, im
 	if err := s.productRepo.AddImages(ctx, productID, imageURLs); err != nil {
 		return nil, err
 	}
+	s.retrainService.MarkChanged(ctx, sellerID)
 
 	for _, url := range imageURLs {
 		addedImages = append(addedImages, entities.Image{
@@ -130,12 +220,12 @@ func (s *productService) AddProductImages(ctx context.Context, productID int, im
 	return addedImages, nil
 }
 
-func (s *productService) TrainProductModel(ctx context.Context, productID int) (*entities.TrainingResponse, error) {
-	product, err := s.productRepo.FindByID(ctx, productID)
+func (s *productService) TrainProductModel(ctx context.Context, sellerID int, productID int) (*entities.TrainingJob, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
 	if err != nil {
 		return nil, err
 	}
-	return s.mlRepo.TrainModel(strconv.Itoa(product.SellerID))
+	return s.trainingService.Enqueue(ctx, product.SellerID, &product.ID, false)
 }
 
 func (s *productService) PredictProduct(ctx context.Context, productID int, image *multipart.FileHeader) (*entities.PredictionResponse, error) {
@@ -155,12 +245,27 @@ func (s *productService) PredictProduct(ctx context.Context, productID int, imag
 		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
 
-	return s.mlRepo.PredictProduct(strconv.Itoa(product.SellerID), imageData)
+	return s.mlRepo.PredictProduct(ctx, product.SellerID, imageData, settingsForPrediction(ctx, s.settingsService, product.SellerID))
 }
 
-func (s *productService) PinProduct(ctx context.Context, productID int, sellerID int, similarityScore float64) error {
+func (s *productService) PinProduct(ctx context.Context, productID int, variantID *int, sellerID int, similarityScore float64) error {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return err
+	}
+
+	if variantID != nil {
+		variant, err := s.variantRepo.FindByID(ctx, *variantID)
+		if err != nil {
+			return err
+		}
+		if variant.ProductID != productID {
+			return fmt.Errorf("%w: variant %d is not a variant of product %d", entities.ErrInvalidInput, variant.ID, productID)
+		}
+	}
+
 	pinData := &entities.PinnedProduct{
 		ProductID:       productID,
+		VariantID:       variantID,
 		SellerID:        sellerID,
 		SimilarityScore: similarityScore,
 		IsPinned:        true,
@@ -168,24 +273,36 @@ func (s *productService) PinProduct(ctx context.Context, productID int, sellerID
 	return s.pinnedRepo.PinProduct(ctx, pinData)
 }
 
-func (s *productService) UnpinProduct(ctx context.Context, productID int, sellerID string) error {
+func (s *productService) UnpinProduct(ctx context.Context, productID int, sellerID int) error {
 	return s.pinnedRepo.UnpinProduct(ctx, productID, sellerID)
 }
 
-func (s *productService) GetPinnedProducts(ctx context.Context, sellerID string) ([]entities.PinnedProduct, error) {
+func (s *productService) GetPinnedProducts(ctx context.Context, sellerID int) ([]entities.PinnedProduct, error) {
 	return s.pinnedRepo.FindPinnedBySellerID(ctx, sellerID)
 }
 
-func (s *productService) UnpinAllProducts(ctx context.Context, sellerID string) (int64, error) {
+func (s *productService) UnpinAllProducts(ctx context.Context, sellerID int) (int64, error) {
 	return s.pinnedRepo.UnpinAllProducts(ctx, sellerID)
 }
 
-func (s *productService) TrainAllSellers(ctx context.Context, sellerID string) (*entities.TrainingResponse, error) {
-	return s.mlRepo.TrainModel(sellerID)
+func (s *productService) TrainAllSellers(ctx context.Context, sellerID int, fineTune bool) (*entities.TrainingJob, error) {
+	return s.trainingService.Enqueue(ctx, sellerID, nil, fineTune)
 }
 
-func (s *productService) GetTrainingStatus(ctx context.Context, sellerID string) (map[string]interface{}, error) {
-	return s.mlRepo.GetTrainingStatus(sellerID)
+func (s *productService) GetTrainingStatus(ctx context.Context, sellerID int) (map[string]interface{}, error) {
+	return s.mlRepo.GetTrainingStatus(ctx, sellerID)
+}
+
+// findOwnedProduct loads a product and checks that it belongs to the seller.
+func (s *productService) findOwnedProduct(ctx context.Context, sellerID int, productID int) (*entities.Product, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("product %d does not belong to seller %d: %w", productID, sellerID, entities.ErrForbidden)
+	}
+	return product, nil
 }
 
 func (s *productService) createMLDataset(productID, sellerID int, productName string) error {
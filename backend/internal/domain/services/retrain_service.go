@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"os"
+	"time"
+)
+
+type RetrainService interface {
+	GetSettings(ctx context.Context, sellerID int) (*entities.CatalogueState, error)
+	UpdateSettings(ctx context.Context, sellerID int, req *entities.RetrainSettingsRequest) (*entities.CatalogueState, error)
+	// MarkChanged records a change to the seller's catalogue. Unless the
+	// seller opted out, it is retrained once the catalogue has been quiet
+	// for the debounce period.
+	MarkChanged(ctx context.Context, sellerID int)
+	// RetrainIfDirty retrains the seller's catalogue straight away if it
+	// has changes the index does not cover, so a stream about to start
+	// recognises them.
+	RetrainIfDirty(ctx context.Context, sellerID int)
+	// RunScheduler retrains catalogues whose debounce period has passed
+	// until ctx is cancelled.
+	RunScheduler(ctx context.Context)
+}
+
+type retrainService struct {
+	catalogueRepo   repositories.CatalogueRepository
+	trainingService TrainingJobService
+	quietPeriod     time.Duration
+	checkInterval   time.Duration
+}
+
+func NewRetrainService(catalogueRepo repositories.CatalogueRepository, trainingService TrainingJobService) RetrainService {
+	quietPeriod, err := time.ParseDuration(os.Getenv("RETRAIN_QUIET_PERIOD"))
+	if err != nil || quietPeriod <= 0 {
+		quietPeriod = 5 * time.Minute
+	}
+	checkInterval, err := time.ParseDuration(os.Getenv("RETRAIN_CHECK_INTERVAL"))
+	if err != nil || checkInterval <= 0 {
+		checkInterval = 30 * time.Second
+	}
+
+	return &retrainService{
+		catalogueRepo:   catalogueRepo,
+		trainingService: trainingService,
+		quietPeriod:     quietPeriod,
+		checkInterval:   checkInterval,
+	}
+}
+
+func (s *retrainService) GetSettings(ctx context.Context, sellerID int) (*entities.CatalogueState, error) {
+	state, err := s.catalogueRepo.FindState(ctx, sellerID)
+	if errors.Is(err, entities.ErrNotFound) {
+		return &entities.CatalogueState{SellerID: sellerID, AutoRetrain: true}, nil
+	}
+	return state, err
+}
+
+func (s *retrainService) UpdateSettings(ctx context.Context, sellerID int, req *entities.RetrainSettingsRequest) (*entities.CatalogueState, error) {
+	return s.catalogueRepo.SaveAutoRetrain(ctx, sellerID, *req.AutoRetrain)
+}
+
+func (s *retrainService) MarkChanged(ctx context.Context, sellerID int) {
+	// The change itself has been saved; at worst it waits for the next
+	// retrain.
+	if err := s.catalogueRepo.MarkDirty(ctx, sellerID); err != nil {
+		log.Printf("Failed to mark catalogue of seller %d as changed: %v", sellerID, err)
+	}
+}
+
+func (s *retrainService) RetrainIfDirty(ctx context.Context, sellerID int) {
+	state, err := s.catalogueRepo.FindState(ctx, sellerID)
+	if err != nil {
+		if !errors.Is(err, entities.ErrNotFound) {
+			log.Printf("Failed to load catalogue state of seller %d: %v", sellerID, err)
+		}
+		return
+	}
+	if state.AutoRetrain && state.DirtySince != nil {
+		s.retrain(ctx, sellerID)
+	}
+}
+
+func (s *retrainService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sellerIDs, err := s.catalogueRepo.FindDue(ctx, s.quietPeriod)
+			if err != nil {
+				log.Printf("Failed to find catalogues due for retraining: %v", err)
+				continue
+			}
+			for _, sellerID := range sellerIDs {
+				s.retrain(ctx, sellerID)
+			}
+		}
+	}
+}
+
+func (s *retrainService) retrain(ctx context.Context, sellerID int) {
+	job, err := s.trainingService.Enqueue(ctx, sellerID, nil, false)
+	if err != nil {
+		// A job already in flight leaves the marker set, so changes it
+		// missed are picked up once it is done.
+		if !errors.Is(err, entities.ErrConflict) {
+			log.Printf("Failed to queue retraining for seller %d: %v", sellerID, err)
+		}
+		return
+	}
+	log.Printf("Queued retraining job %d for the changed catalogue of seller %d", job.ID, sellerID)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxVisualSearchSellers caps how many seller indexes one visual search
+	// queries.
+	maxVisualSearchSellers = 20
+	// visualSearchConcurrency is how many seller indexes are queried at once.
+	visualSearchConcurrency  = 4
+	maxVisualSearchImageSize = 10 << 20
+)
+
+type SearchService interface {
+	// VisualSearch matches a photo against the sellers' product indexes and
+	// returns the matching products ranked by similarity.
+	VisualSearch(ctx context.Context, params *entities.VisualSearchParams, image *multipart.FileHeader) (*entities.VisualSearchResponse, error)
+}
+
+type searchService struct {
+	mlRepo          repositories.MLRepository
+	productRepo     repositories.ProductRepository
+	liveStreamRepo  repositories.LiveStreamRepository
+	settingsService DetectionSettingsService
+}
+
+func NewSearchService(
+	mlRepo repositories.MLRepository,
+	productRepo repositories.ProductRepository,
+	liveStreamRepo repositories.LiveStreamRepository,
+	settingsService DetectionSettingsService,
+) SearchService {
+	return &searchService{
+		mlRepo:          mlRepo,
+		productRepo:     productRepo,
+		liveStreamRepo:  liveStreamRepo,
+		settingsService: settingsService,
+	}
+}
+
+// sellerMatch is one product a seller's index matched.
+type sellerMatch struct {
+	sellerID        int
+	productID       int
+	similarityScore float64
+	confidence      float64
+}
+
+func (s *searchService) VisualSearch(ctx context.Context, params *entities.VisualSearchParams, image *multipart.FileHeader) (*entities.VisualSearchResponse, error) {
+	if image.Size > maxVisualSearchImageSize {
+		return nil, fmt.Errorf("%w: image is larger than %d bytes", entities.ErrInvalidInput, maxVisualSearchImageSize)
+	}
+
+	sellerIDs, err := s.searchSellers(params.SellerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := image.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer src.Close()
+
+	imageData, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+
+	response := &entities.VisualSearchResponse{
+		Data:            []entities.VisualSearchHit{},
+		SearchedSellers: sellerIDs,
+	}
+	if len(sellerIDs) == 0 {
+		return response, nil
+	}
+
+	matches, failed, err := s.fanOut(ctx, sellerIDs, imageData)
+	response.FailedSellers = failed
+	if len(failed) == len(sellerIDs) {
+		return nil, fmt.Errorf("visual search failed for all %d sellers: %w", len(sellerIDs), err)
+	}
+
+	// Keep each product's best match. A product can be matched by several
+	// detections in the same photo.
+	best := make(map[int]sellerMatch)
+	for _, match := range matches {
+		if match.similarityScore < params.MinScore {
+			continue
+		}
+		if current, ok := best[match.productID]; !ok || match.similarityScore > current.similarityScore {
+			best[match.productID] = match
+		}
+	}
+
+	productIDs := make([]int, 0, len(best))
+	for productID := range best {
+		productIDs = append(productIDs, productID)
+	}
+	if len(productIDs) == 0 {
+		return response, nil
+	}
+
+	products, err := s.productRepo.FindByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		match := best[product.ID]
+		// An index can lag behind the catalogue, so drop products that have
+		// since moved out of the seller whose index matched them.
+		if product.SellerID != match.sellerID {
+			continue
+		}
+		response.Data = append(response.Data, entities.VisualSearchHit{
+			Product:         product,
+			SimilarityScore: match.similarityScore,
+			Confidence:      match.confidence,
+		})
+	}
+
+	sort.Slice(response.Data, func(i, j int) bool {
+		if response.Data[i].SimilarityScore != response.Data[j].SimilarityScore {
+			return response.Data[i].SimilarityScore > response.Data[j].SimilarityScore
+		}
+		return response.Data[i].Product.ID < response.Data[j].Product.ID
+	})
+	if limit := entities.PageLimit(params.Limit); len(response.Data) > limit {
+		response.Data = response.Data[:limit]
+	}
+
+	return response, nil
+}
+
+// searchSellers parses the requested seller IDs, falling back to the
+// sellers that are live right now.
+func (s *searchService) searchSellers(raw string) ([]int, error) {
+	seen := make(map[int]bool)
+	sellerIDs := []int{}
+
+	if strings.TrimSpace(raw) != "" {
+		for _, part := range strings.Split(raw, ",") {
+			sellerID, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || sellerID <= 0 {
+				return nil, fmt.Errorf("%w: invalid seller id %q", entities.ErrInvalidInput, part)
+			}
+			if !seen[sellerID] {
+				seen[sellerID] = true
+				sellerIDs = append(sellerIDs, sellerID)
+			}
+		}
+		if len(sellerIDs) > maxVisualSearchSellers {
+			return nil, fmt.Errorf("%w: at most %d sellers can be searched at once", entities.ErrInvalidInput, maxVisualSearchSellers)
+		}
+		return sellerIDs, nil
+	}
+
+	isLive := true
+	page, err := s.liveStreamRepo.ListLiveStreams(&entities.LiveStreamFilter{
+		IsLive: &isLive,
+		Sort:   entities.LiveStreamSortViewers,
+		Limit:  entities.MaxPageLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range page.Data {
+		if !seen[stream.SellerID] && len(sellerIDs) < maxVisualSearchSellers {
+			seen[stream.SellerID] = true
+			sellerIDs = append(sellerIDs, stream.SellerID)
+		}
+	}
+	return sellerIDs, nil
+}
+
+// searchSettings are the seller's detection settings without the region of
+// interest, which frames the seller's camera rather than a shopper's photo.
+func (s *searchService) searchSettings(ctx context.Context, sellerID int) *entities.DetectionSettings {
+	settings := settingsForPrediction(ctx, s.settingsService, sellerID)
+	if settings == nil || settings.ROI == nil {
+		return settings
+	}
+	withoutROI := *settings
+	withoutROI.ROI = nil
+	return &withoutROI
+}
+
+// fanOut queries every seller's index with a bounded number of concurrent
+// requests. Sellers whose query fails are reported rather than failing the
+// whole search, along with the last error seen.
+func (s *searchService) fanOut(ctx context.Context, sellerIDs []int, imageData []byte) ([]sellerMatch, []int, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		matches []sellerMatch
+		failed  []int
+		lastErr error
+	)
+	sem := make(chan struct{}, visualSearchConcurrency)
+
+	for _, sellerID := range sellerIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(sellerID int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.mlRepo.PredictProduct(ctx, sellerID, imageData, s.searchSettings(ctx, sellerID))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("Visual search failed for seller %d: %v", sellerID, err)
+				failed = append(failed, sellerID)
+				lastErr = err
+				return
+			}
+			for _, prediction := range result.Predictions {
+				productID, err := strconv.Atoi(prediction.ProductID)
+				if err != nil {
+					continue
+				}
+				matches = append(matches, sellerMatch{
+					sellerID:        sellerID,
+					productID:       productID,
+					similarityScore: prediction.SimilarityScore,
+					confidence:      prediction.Confidence,
+				})
+			}
+		}(sellerID)
+	}
+
+	wg.Wait()
+	sort.Ints(failed)
+	return matches, failed, lastErr
+}
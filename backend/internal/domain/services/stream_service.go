@@ -2,29 +2,61 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
 	"mime/multipart"
+	"strconv"
+	"time"
 )
 
 type StreamService interface {
-	ProcessStreamFrame(ctx context.Context, sellerID string, frame *multipart.FileHeader) (*entities.PredictionResponse, error)
-	PredictFrame(ctx context.Context, sellerID string, frame *multipart.FileHeader) (*entities.PredictionResponse, error)
+	ProcessStreamFrame(ctx context.Context, sellerID int, frame *multipart.FileHeader) (*entities.PredictionResponse, error)
+	// ProcessFrame runs detection on one frame of the seller's stream,
+	// records the products found against the seller's live stream at
+	// capturedAt, or now if that is later, and feeds the result to the
+	// seller's auto-pin policy.
+	ProcessFrame(ctx context.Context, sellerID int, frameData []byte, capturedAt time.Time) (*entities.PredictionResponse, error)
+	// ProcessStreamFrames runs detection on a burst of frames from the
+	// seller's stream in one ML request and handles each result like
+	// ProcessFrame, in the order the frames were sent. capturedAt holds
+	// each frame's capture time, or is empty to record them all at the time
+	// of the request. A frame that could not be predicted carries its error
+	// instead of failing the burst.
+	ProcessStreamFrames(ctx context.Context, sellerID int, frames []*multipart.FileHeader, capturedAt []time.Time) (*entities.BatchPredictionResponse, error)
+	PredictFrame(ctx context.Context, sellerID int, frame *multipart.FileHeader) (*entities.PredictionResponse, error)
 }
 
 type streamService struct {
 	mlRepo    repositories.MLRepository
 	pinnedRepo repositories.PinnedProductRepository
+	liveStreamRepo repositories.LiveStreamRepository
+	detectionRepo repositories.DetectionRepository
+	autoPinService AutoPinService
+	detectionSettingsService DetectionSettingsService
 }
 
-func NewStreamService(mlRepo repositories.MLRepository, pinnedRepo repositories.PinnedProductRepository) StreamService {
+func NewStreamService(
+	mlRepo repositories.MLRepository,
+	pinnedRepo repositories.PinnedProductRepository,
+	liveStreamRepo repositories.LiveStreamRepository,
+	detectionRepo repositories.DetectionRepository,
+	autoPinService AutoPinService,
+	detectionSettingsService DetectionSettingsService,
+) StreamService {
 	return &streamService{
 		mlRepo:    mlRepo,
 		pinnedRepo: pinnedRepo,
+		liveStreamRepo: liveStreamRepo,
+		detectionRepo: detectionRepo,
+		autoPinService: autoPinService,
+		detectionSettingsService: detectionSettingsService,
 	}
 }
 
-func (s *streamService) ProcessStreamFrame(ctx context.Context, sellerID string, frame *multipart.FileHeader) (*entities.PredictionResponse, error) {
+func (s *streamService) ProcessStreamFrame(ctx context.Context, sellerID int, frame *multipart.FileHeader) (*entities.PredictionResponse, error) {
 	src, err := frame.Open()
 	if err != nil {
 		return nil, err
@@ -36,10 +68,113 @@ func (s *streamService) ProcessStreamFrame(ctx context.Context, sellerID string,
 		return nil, err
 	}
 
-	return s.mlRepo.ProcessStreamFrame(sellerID, frameData)
+	return s.ProcessFrame(ctx, sellerID, frameData, time.Now())
 }
 
-func (s *streamService) PredictFrame(ctx context.Context, sellerID string, frame *multipart.FileHeader) (*entities.PredictionResponse, error) {
+func (s *streamService) ProcessFrame(ctx context.Context, sellerID int, frameData []byte, capturedAt time.Time) (*entities.PredictionResponse, error) {
+	capturedAt = clampCaptureTime(capturedAt, time.Now())
+	result, err := s.mlRepo.ProcessStreamFrame(ctx, sellerID, frameData, settingsForPrediction(ctx, s.detectionSettingsService, sellerID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordDetections(ctx, sellerID, capturedAt, result); err != nil {
+		log.Printf("Failed to record detections for seller %d: %v", sellerID, err)
+	}
+	s.autoPinService.Observe(ctx, sellerID, result)
+	return result, nil
+}
+
+func (s *streamService) ProcessStreamFrames(ctx context.Context, sellerID int, frames []*multipart.FileHeader, capturedAt []time.Time) (*entities.BatchPredictionResponse, error) {
+	receivedAt := time.Now()
+	if len(frames) == 0 || len(frames) > entities.MaxBatchFrames {
+		return nil, fmt.Errorf("%w: send between 1 and %d frames", entities.ErrInvalidInput, entities.MaxBatchFrames)
+	}
+	if len(capturedAt) != 0 && len(capturedAt) != len(frames) {
+		return nil, fmt.Errorf("%w: send one captured_at per frame or none", entities.ErrInvalidInput)
+	}
+
+	frameData := make([][]byte, 0, len(frames))
+	for _, frame := range frames {
+		data, err := readFrame(frame)
+		if err != nil {
+			return nil, err
+		}
+		frameData = append(frameData, data)
+	}
+
+	results, err := s.mlRepo.PredictBatch(ctx, sellerID, frameData, settingsForPrediction(ctx, s.detectionSettingsService, sellerID))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if result.PredictionResponse == nil {
+			continue
+		}
+		frameCapturedAt := receivedAt
+		if len(capturedAt) != 0 {
+			frameCapturedAt = clampCaptureTime(capturedAt[i], receivedAt)
+		}
+		if err := s.recordDetections(ctx, sellerID, frameCapturedAt, result.PredictionResponse); err != nil {
+			log.Printf("Failed to record detections for seller %d: %v", sellerID, err)
+		}
+		s.autoPinService.Observe(ctx, sellerID, result.PredictionResponse)
+	}
+	return &entities.BatchPredictionResponse{Results: results}, nil
+}
+
+// clampCaptureTime keeps a capture time reported by the seller's clock from
+// lying after the frame reached the server.
+func clampCaptureTime(capturedAt, receivedAt time.Time) time.Time {
+	if capturedAt.IsZero() || capturedAt.After(receivedAt) {
+		return receivedAt
+	}
+	return capturedAt
+}
+
+func readFrame(frame *multipart.FileHeader) ([]byte, error) {
+	src, err := frame.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return io.ReadAll(src)
+}
+
+// recordDetections stores the frame's recognised products against the
+// seller's live stream. Frames sent while the seller is not live are not
+// recorded.
+func (s *streamService) recordDetections(ctx context.Context, sellerID int, capturedAt time.Time, result *entities.PredictionResponse) error {
+	if len(result.Predictions) == 0 {
+		return nil
+	}
+
+	stream, err := s.liveStreamRepo.GetLiveStreamBySellerID(sellerID)
+	if err != nil {
+		return nil
+	}
+
+	detections := make([]entities.Detection, 0, len(result.Predictions))
+	for _, prediction := range result.Predictions {
+		productID, err := strconv.Atoi(prediction.ProductID)
+		if err != nil || len(prediction.BBox) != 4 {
+			continue
+		}
+		detections = append(detections, entities.Detection{
+			LiveStreamID: stream.ID,
+			ProductID:    productID,
+			DetectedAt:   capturedAt,
+			BBox:         [4]int{prediction.BBox[0], prediction.BBox[1], prediction.BBox[2], prediction.BBox[3]},
+			Similarity:   prediction.SimilarityScore,
+			Confidence:   prediction.Confidence,
+		})
+	}
+	return s.detectionRepo.Record(ctx, detections)
+}
+
+func (s *streamService) PredictFrame(ctx context.Context, sellerID int, frame *multipart.FileHeader) (*entities.PredictionResponse, error) {
 	src, err := frame.Open()
 	if err != nil {
 		return nil, err
@@ -51,5 +186,5 @@ func (s *streamService) PredictFrame(ctx context.Context, sellerID string, frame
 		return nil, err
 	}
 
-	return s.mlRepo.PredictProduct(sellerID, frameData)
+	return s.mlRepo.PredictProduct(ctx, sellerID, frameData, settingsForPrediction(ctx, s.detectionSettingsService, sellerID))
 }
\ No newline at end of file
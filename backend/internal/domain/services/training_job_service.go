@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// trainingJobLease is how long a job stays with the replica running it
+// without being renewed. The runner renews it three times per lease.
+const trainingJobLease = time.Minute
+
+type TrainingJobService interface {
+	// Enqueue starts training the seller's catalogue in the background and
+	// returns the queued job. It returns entities.ErrConflict while the
+	// seller has another job queued or running.
+	Enqueue(ctx context.Context, sellerID int, productID *int, fineTune bool) (*entities.TrainingJob, error)
+	GetJob(ctx context.Context, sellerID int, id int) (*entities.TrainingJob, error)
+	ListJobs(ctx context.Context, sellerID int, filter *entities.TrainingJobFilter) (*entities.TrainingJobPage, error)
+	// ResumeJobs picks up the jobs a previous run of the backend left
+	// queued or running. Jobs another replica is still running are left to
+	// it.
+	ResumeJobs(ctx context.Context)
+}
+
+type trainingJobService struct {
+	jobRepo       repositories.TrainingJobRepository
+	catalogueRepo repositories.CatalogueRepository
+	mlRepo        repositories.MLRepository
+	webrtcRepo    repositories.WebRTCRepository
+	pollInterval  time.Duration
+	jobTimeout    time.Duration
+	// runnerID identifies this process as the holder of job leases.
+	runnerID string
+}
+
+func NewTrainingJobService(
+	jobRepo repositories.TrainingJobRepository,
+	catalogueRepo repositories.CatalogueRepository,
+	mlRepo repositories.MLRepository,
+	webrtcRepo repositories.WebRTCRepository,
+) TrainingJobService {
+	pollInterval, err := time.ParseDuration(os.Getenv("TRAINING_POLL_INTERVAL"))
+	if err != nil || pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	jobTimeout, err := time.ParseDuration(os.Getenv("TRAINING_JOB_TIMEOUT"))
+	if err != nil || jobTimeout <= 0 {
+		jobTimeout = time.Hour
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	hostname, _ := os.Hostname()
+
+	return &trainingJobService{
+		jobRepo:       jobRepo,
+		catalogueRepo: catalogueRepo,
+		mlRepo:        mlRepo,
+		webrtcRepo:    webrtcRepo,
+		pollInterval:  pollInterval,
+		jobTimeout:    jobTimeout,
+		runnerID:      hostname + "-" + hex.EncodeToString(buf),
+	}
+}
+
+func (s *trainingJobService) Enqueue(ctx context.Context, sellerID int, productID *int, fineTune bool) (*entities.TrainingJob, error) {
+	job := &entities.TrainingJob{
+		SellerID:  sellerID,
+		ProductID: productID,
+		FineTune:  fineTune,
+		Message:   "Waiting for the ML service",
+	}
+	if err := s.jobRepo.Create(ctx, job, s.runnerID, trainingJobLease); err != nil {
+		if errors.Is(err, entities.ErrConflict) {
+			return nil, fmt.Errorf("%w: seller %d already has a training job in progress", entities.ErrConflict, sellerID)
+		}
+		return nil, err
+	}
+	s.notify(job)
+
+	// The ML service reads the catalogue after the job starts, so every
+	// change made until now is covered by it.
+	if err := s.catalogueRepo.MarkClean(ctx, sellerID); err != nil {
+		log.Printf("Failed to mark catalogue of seller %d as trained: %v", sellerID, err)
+	}
+
+	// The runner gets its own copy so the caller can serialise the job
+	// while it changes.
+	running := *job
+	go s.run(&running)
+
+	return job, nil
+}
+
+func (s *trainingJobService) GetJob(ctx context.Context, sellerID int, id int) (*entities.TrainingJob, error) {
+	job, err := s.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.SellerID != sellerID {
+		return nil, fmt.Errorf("training job %d does not belong to seller %d: %w", id, sellerID, entities.ErrForbidden)
+	}
+	return job, nil
+}
+
+func (s *trainingJobService) ListJobs(ctx context.Context, sellerID int, filter *entities.TrainingJobFilter) (*entities.TrainingJobPage, error) {
+	return s.jobRepo.List(ctx, sellerID, filter)
+}
+
+func (s *trainingJobService) ResumeJobs(ctx context.Context) {
+	jobs, err := s.jobRepo.ClaimAbandoned(ctx, s.runnerID, trainingJobLease)
+	if err != nil {
+		log.Printf("Failed to load unfinished training jobs: %v", err)
+		return
+	}
+	for i := range jobs {
+		log.Printf("Resuming %s training job %d for seller %d", jobs[i].Status, jobs[i].ID, jobs[i].SellerID)
+		go s.run(&jobs[i])
+	}
+}
+
+// run starts the job on the ML service if it has not been started yet and
+// then follows it until it finishes or times out.
+func (s *trainingJobService) run(job *entities.TrainingJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.jobTimeout)
+	defer cancel()
+
+	var lost atomic.Bool
+	go s.keepLease(ctx, job.ID, &lost, cancel)
+
+	if job.Status == entities.TrainingJobQueued {
+		// Training is not retried: the ML service may have started it even
+		// if the response got lost.
+		if _, err := s.mlRepo.TrainModel(ctx, job.SellerID, job.FineTune); err != nil {
+			if lost.Load() {
+				return
+			}
+			s.fail(job, fmt.Sprintf("failed to start training: %v", err))
+			return
+		}
+		job.Status = entities.TrainingJobRunning
+		job.Message = "Training started"
+		s.save(job)
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			if lost.Load() {
+				return
+			}
+			reason := fmt.Sprintf("training did not finish within %s", s.jobTimeout)
+			if lastErr != nil {
+				reason += fmt.Sprintf(": %v", lastErr)
+			}
+			s.fail(job, reason)
+			return
+		case <-ticker.C:
+		}
+
+		status, err := s.mlRepo.GetTrainingStatus(ctx, job.SellerID)
+		if err != nil {
+			// The ML service may be restarting; keep asking until the job
+			// times out.
+			lastErr = err
+			continue
+		}
+		lastErr = nil
+
+		if s.advance(job, status) {
+			return
+		}
+	}
+}
+
+// keepLease renews the job's lease until ctx ends. If the job has been taken
+// over by another replica it sets lost and cancels the run, which then stops
+// without touching the job.
+func (s *trainingJobService) keepLease(ctx context.Context, jobID int, lost *atomic.Bool, cancel context.CancelFunc) {
+	ticker := time.NewTicker(trainingJobLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := s.jobRepo.RenewLease(ctx, jobID, s.runnerID, trainingJobLease)
+		if err != nil {
+			// Try again on the next tick; the lease has time left.
+			log.Printf("Failed to renew the lease on training job %d: %v", jobID, err)
+			continue
+		}
+		if !held {
+			log.Printf("Training job %d is no longer run by this replica", jobID)
+			lost.Store(true)
+			cancel()
+			return
+		}
+	}
+}
+
+// advance applies a status report from the ML service to the job and
+// reports whether the job has finished.
+func (s *trainingJobService) advance(job *entities.TrainingJob, status map[string]interface{}) bool {
+	state, _ := status["status"].(string)
+	message, _ := status["message"].(string)
+
+	switch state {
+	case "training":
+		progress := job.Progress
+		if value, ok := status["progress"].(float64); ok && value >= 0 && value <= 100 {
+			progress = int(value)
+		}
+		if progress == job.Progress && message == job.Message {
+			return false
+		}
+		job.Progress, job.Message = progress, message
+		s.save(job)
+		return false
+	case "completed":
+		job.Status = entities.TrainingJobCompleted
+		job.Progress = 100
+		job.Message = message
+		s.save(job)
+		return true
+	case "error":
+		s.fail(job, message)
+		return true
+	default:
+		// The ML service only keeps statuses in memory, so a restart
+		// forgets the run.
+		s.fail(job, fmt.Sprintf("ML service has no record of the training run (status %q)", state))
+		return true
+	}
+}
+
+func (s *trainingJobService) fail(job *entities.TrainingJob, reason string) {
+	log.Printf("Training job %d for seller %d failed: %s", job.ID, job.SellerID, reason)
+	job.Status = entities.TrainingJobFailed
+	job.Error = &reason
+	s.save(job)
+
+	// The index was not rebuilt, so the catalogue is still ahead of it. It
+	// is retried with backoff rather than as if it had just changed.
+	if err := s.catalogueRepo.MarkFailed(context.Background(), job.SellerID); err != nil {
+		log.Printf("Failed to mark catalogue of seller %d as changed: %v", job.SellerID, err)
+	}
+}
+
+// save persists the job's state and pushes it to the seller. It does not
+// use the job's context, which may be what just expired.
+func (s *trainingJobService) save(job *entities.TrainingJob) {
+	if err := s.jobRepo.Update(context.Background(), job); err != nil {
+		log.Printf("Failed to save training job %d: %v", job.ID, err)
+	}
+	s.notify(job)
+}
+
+// notify sends the job to the seller's own connections to their room: the
+// dashboard and, while they are live, the publisher.
+func (s *trainingJobService) notify(job *entities.TrainingJob) {
+	messageType := entities.MessageTypeTrainingProgress
+	switch job.Status {
+	case entities.TrainingJobCompleted:
+		messageType = entities.MessageTypeTrainingCompleted
+	case entities.TrainingJobFailed:
+		messageType = entities.MessageTypeTrainingFailed
+	}
+
+	roomID := entities.SellerRoomID(job.SellerID)
+	for _, client := range s.webrtcRepo.GetRoomClients(roomID) {
+		if client.Role != entities.RolePublisher && client.Role != entities.RoleDashboard {
+			continue
+		}
+		s.webrtcRepo.SendToClient(roomID, client.ID, entities.WebRTCMessage{
+			Type: messageType,
+			Data: *job,
+			Room: roomID,
+			To:   client.ID,
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"mime/multipart"
+	"strings"
+)
+
+type VariantService interface {
+	GetVariants(ctx context.Context, productID int) (*entities.ProductVariantsResponse, error)
+	// SetOptions replaces the product's option axes. Existing variants must
+	// still fit the new axes.
+	SetOptions(ctx context.Context, sellerID int, productID int, options []entities.ProductOption) ([]entities.ProductOption, error)
+	CreateVariant(ctx context.Context, sellerID int, productID int, req *entities.VariantRequest) (*entities.ProductVariant, error)
+	UpdateVariant(ctx context.Context, sellerID int, productID int, variantID int, req *entities.VariantRequest) (*entities.ProductVariant, error)
+	DeleteVariant(ctx context.Context, sellerID int, productID int, variantID int) error
+	AddVariantImages(ctx context.Context, sellerID int, productID int, variantID int, images []*multipart.FileHeader) ([]entities.Image, error)
+}
+
+type variantService struct {
+	variantRepo      repositories.VariantRepository
+	productRepo      repositories.ProductRepository
+	storageRepo      repositories.StorageRepository
+	inventoryService InventoryService
+	retrainService   RetrainService
+}
+
+func NewVariantService(
+	variantRepo repositories.VariantRepository,
+	productRepo repositories.ProductRepository,
+	storageRepo repositories.StorageRepository,
+	inventoryService InventoryService,
+	retrainService RetrainService,
+) VariantService {
+	return &variantService{
+		variantRepo:      variantRepo,
+		productRepo:      productRepo,
+		storageRepo:      storageRepo,
+		inventoryService: inventoryService,
+		retrainService:   retrainService,
+	}
+}
+
+func (s *variantService) GetVariants(ctx context.Context, productID int) (*entities.ProductVariantsResponse, error) {
+	if _, err := s.productRepo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	options, err := s.variantRepo.FindOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.variantRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ProductVariantsResponse{Options: options, Variants: variants}, nil
+}
+
+func (s *variantService) SetOptions(ctx context.Context, sellerID int, productID int, options []entities.ProductOption) ([]entities.ProductOption, error) {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for i := range options {
+		option := &options[i]
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" {
+			return nil, fmt.Errorf("%w: option name is required", entities.ErrInvalidInput)
+		}
+		if names[option.Name] {
+			return nil, fmt.Errorf("%w: duplicate option %q", entities.ErrInvalidInput, option.Name)
+		}
+		names[option.Name] = true
+
+		values := make(map[string]bool)
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return nil, fmt.Errorf("%w: option %q has empty or duplicate values", entities.ErrInvalidInput, option.Name)
+			}
+			values[value] = true
+		}
+	}
+
+	variants, err := s.variantRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		if err := matchOptions(options, variant.Options); err != nil {
+			return nil, fmt.Errorf("variant %d no longer fits the options (%v): %w", variant.ID, err, entities.ErrConflict)
+		}
+	}
+
+	if err := s.variantRepo.ReplaceOptions(ctx, productID, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func (s *variantService) CreateVariant(ctx context.Context, sellerID int, productID int, req *entities.VariantRequest) (*entities.ProductVariant, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.variantRepo.FindOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("%w: product %d has no options to vary", entities.ErrInvalidInput, productID)
+	}
+	if err := matchOptions(options, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant := &entities.ProductVariant{
+		ProductID: productID,
+		SKU:       strings.TrimSpace(req.SKU),
+		Options:   req.Options,
+		Price:     product.Price,
+	}
+	if req.Price != nil {
+		variant.Price = *req.Price
+	}
+	if req.Stock != nil {
+		variant.Stock = *req.Stock
+	}
+
+	if err := s.variantRepo.Create(ctx, variant); err != nil {
+		return nil, err
+	}
+	variant.Available = variant.Stock
+	s.inventoryService.PublishStock(ctx, productID)
+	return variant, nil
+}
+
+func (s *variantService) UpdateVariant(ctx context.Context, sellerID int, productID int, variantID int, req *entities.VariantRequest) (*entities.ProductVariant, error) {
+	variant, err := s.findOwnedVariant(ctx, sellerID, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.variantRepo.FindOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := matchOptions(options, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant.SKU = strings.TrimSpace(req.SKU)
+	variant.Options = req.Options
+	if req.Price != nil {
+		variant.Price = *req.Price
+	}
+
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return nil, err
+	}
+
+	if req.Stock != nil {
+		if _, err := s.inventoryService.SetVariantStock(ctx, sellerID, productID, variantID, *req.Stock); err != nil {
+			return nil, err
+		}
+	}
+	return s.findVariant(ctx, productID, variantID)
+}
+
+func (s *variantService) DeleteVariant(ctx context.Context, sellerID int, productID int, variantID int) error {
+	if _, err := s.findOwnedVariant(ctx, sellerID, productID, variantID); err != nil {
+		return err
+	}
+
+	if err := s.variantRepo.Delete(ctx, variantID); err != nil {
+		return err
+	}
+	s.inventoryService.PublishStock(ctx, productID)
+	// The variant's images went with it.
+	s.retrainService.MarkChanged(ctx, sellerID)
+	return nil
+}
+
+func (s *variantService) AddVariantImages(ctx context.Context, sellerID int, productID int, variantID int, images []*multipart.FileHeader) ([]entities.Image, error) {
+	if _, err := s.findOwnedVariant(ctx, sellerID, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	var imageURLs []string
+	var addedImages []entities.Image
+	for _, file := range images {
+		imageURL, err := s.storageRepo.UploadFromForm(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+		imageURLs = append(imageURLs, imageURL)
+		addedImages = append(addedImages, entities.Image{
+			ProductID: productID,
+			VariantID: &variantID,
+			ImageURL:  imageURL,
+		})
+	}
+
+	if err := s.variantRepo.AddImages(ctx, productID, variantID, imageURLs); err != nil {
+		return nil, fmt.Errorf("failed to add images: %w", err)
+	}
+	// Variant images are indexed with their product's.
+	s.retrainService.MarkChanged(ctx, sellerID)
+	return addedImages, nil
+}
+
+func (s *variantService) findOwnedProduct(ctx context.Context, sellerID int, productID int) (*entities.Product, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("product %d does not belong to seller %d: %w", productID, sellerID, entities.ErrForbidden)
+	}
+	return product, nil
+}
+
+// findOwnedVariant loads a variant and checks that it belongs to the
+// product and the product to the seller.
+func (s *variantService) findOwnedVariant(ctx context.Context, sellerID int, productID int, variantID int) (*entities.ProductVariant, error) {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return nil, err
+	}
+
+	variant, err := s.variantRepo.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != productID {
+		return nil, fmt.Errorf("variant %d of product %d: %w", variantID, productID, entities.ErrNotFound)
+	}
+	return variant, nil
+}
+
+// findVariant reloads a variant with its images and available stock.
+func (s *variantService) findVariant(ctx context.Context, productID int, variantID int) (*entities.ProductVariant, error) {
+	variants, err := s.variantRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range variants {
+		if variants[i].ID == variantID {
+			return &variants[i], nil
+		}
+	}
+	return nil, entities.ErrNotFound
+}
+
+// matchOptions checks that values picks exactly one allowed value for every
+// option axis.
+func matchOptions(options []entities.ProductOption, values map[string]string) error {
+	if len(values) != len(options) {
+		return fmt.Errorf("%w: a variant needs exactly one value for each of the %d options", entities.ErrInvalidInput, len(options))
+	}
+
+	for _, option := range options {
+		value, ok := values[option.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing value for option %q", entities.ErrInvalidInput, option.Name)
+		}
+
+		allowed := false
+		for _, candidate := range option.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %q is not a value of option %q", entities.ErrInvalidInput, value, option.Name)
+		}
+	}
+	return nil
+}
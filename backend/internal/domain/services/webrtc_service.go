@@ -1,6 +1,8 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
@@ -13,11 +15,11 @@ import (
 )
 
 type WebRTCService interface {
-	HandleWebSocketConnection(conn *websocket.Conn) error
-	HandleClientJoin(roomID, clientID, role string, conn *websocket.Conn) error
+	HandleWebSocketConnection(conn *websocket.Conn, claims *entities.StreamClaims) error
+	HandleClientJoin(roomID string, join *entities.JoinPayload, claims *entities.StreamClaims, conn *websocket.Conn) (*entities.Client, error)
 	HandleOffer(roomID, clientID string, offer webrtc.SessionDescription, targetClientID string) error
 	HandleAnswer(roomID, clientID string, answer webrtc.SessionDescription, targetClientID string) error
-	HandleICECandidate(roomID, clientID string, candidateData map[string]interface{}, targetClientID string) error
+	HandleICECandidate(roomID, clientID string, candidate entities.ICECandidatePayload, targetClientID string) error
 	CreatePeerConnection(role string) (*webrtc.PeerConnection, error)
 	CleanupRoom(roomID string)
 	GetRoomStats(roomID string) map[string]interface{}
@@ -27,11 +29,14 @@ type webrtcService struct {
 	repo            repositories.WebRTCRepository
 	liveStreamRepo  repositories.LiveStreamRepository
 	config          entities.WebRTCConfig
+	frameSampler    *frameSampler
+	processFrame    frameDetector
+	frameMaxAge     time.Duration
 	roomsMutex      sync.RWMutex
 	cleanupTime     time.Duration
 }
 
-func NewWebRTCService(repo repositories.WebRTCRepository, liveStreamRepo repositories.LiveStreamRepository) WebRTCService {
+func NewWebRTCService(repo repositories.WebRTCRepository, liveStreamRepo repositories.LiveStreamRepository, streamService StreamService) WebRTCService {
 	stunServers := []string{
 		"stun:stun.l.google.com:19302",
 		"stun:stun1.l.google.com:19302",
@@ -62,179 +67,344 @@ func NewWebRTCService(repo repositories.WebRTCRepository, liveStreamRepo reposit
 		SDPSemantics: webrtc.SDPSemanticsUnifiedPlan,
 	}
 
-	return &webrtcService{
+	frameMaxAge, err := time.ParseDuration(os.Getenv("FRAME_MAX_AGE"))
+	if err != nil || frameMaxAge <= 0 {
+		frameMaxAge = 5 * time.Second
+	}
+
+	service := &webrtcService{
 		repo:           repo,
 		liveStreamRepo: liveStreamRepo,
 		config:         config,
+		processFrame:   streamService.ProcessFrame,
+		frameMaxAge:    frameMaxAge,
 		cleanupTime:    1 * time.Hour,
 	}
+	service.frameSampler = newFrameSampler(repo, streamService.ProcessFrame, service.CreatePeerConnection)
+
+	return service
+}
+
+// signalingSession tracks the state of a single WebSocket connection between
+// its join and its close.
+type signalingSession struct {
+	conn     *websocket.Conn
+	client   *entities.Client
+	claims   *entities.StreamClaims
+	roomID   string
+	clientID string
+	role     string
+	version  int
+	// frames runs detection on the binary frames the publisher sends.
+	frames *frameIngest
+}
+
+// writeJSON goes through the joined client, if any, so replies to this
+// connection never race with broadcasts to the room.
+func (session *signalingSession) writeJSON(v interface{}) error {
+	if session.client != nil {
+		return session.client.WriteJSON(v)
+	}
+	return session.conn.WriteJSON(v)
+}
+
+// sellerOnlyMessageTypes may only be sent by the publisher of a room.
+var sellerOnlyMessageTypes = map[string]bool{
+	entities.MessageTypeSellerLive:      true,
+	entities.MessageTypeSellerOffline:   true,
+	entities.MessageTypeProductPinned:   true,
+	entities.MessageTypeProductUnpinned: true,
 }
 
-func (s *webrtcService) HandleWebSocketConnection(conn *websocket.Conn) error {
-	var roomID, clientID string
+func (s *webrtcService) HandleWebSocketConnection(conn *websocket.Conn, claims *entities.StreamClaims) error {
+	session := &signalingSession{conn: conn, claims: claims}
+	conn.SetReadLimit(entities.MaxSignalingMessageSize)
 
 	for {
-		var msg map[string]interface{}
-		if err := conn.ReadJSON(&msg); err != nil {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
 			break
 		}
 
-		// Track client info for cleanup
-		if msgType, ok := msg["type"].(string); ok && msgType == "join" {
-			roomID, _ = msg["room"].(string)
-			if data, ok := msg["data"].(map[string]interface{}); ok {
-				clientID, _ = data["client_id"].(string)
+		if messageType == websocket.BinaryMessage {
+			if err := s.handleFrame(session, data); err != nil {
+				s.sendError(session, &entities.SignalingEnvelope{Type: entities.MessageTypeFrame, Room: session.roomID}, err)
 			}
+			continue
 		}
 
-		if err := s.handleMessage(conn, msg); err != nil {
+		var envelope entities.SignalingEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			s.sendError(session, &envelope, entities.NewSignalingError(entities.SignalingErrInvalidMessage, "message is not valid JSON"))
+			continue
+		}
+		if envelope.Type == "" {
+			s.sendError(session, &envelope, entities.NewSignalingError(entities.SignalingErrInvalidMessage, "missing message type"))
+			continue
+		}
+
+		if err := s.handleMessage(session, &envelope); err != nil {
+			s.sendError(session, &envelope, err)
 		}
 	}
 
 	// Cleanup when connection closes
-	if roomID != "" && clientID != "" {
-		s.cleanupClient(roomID, clientID)
+	if session.frames != nil {
+		session.frames.close()
+	}
+	if session.roomID != "" && session.clientID != "" {
+		s.cleanupClient(session.roomID, session.clientID)
 	}
 
 	return nil
 }
 
-func (s *webrtcService) handleMessage(conn *websocket.Conn, msg map[string]interface{}) error {
-	msgType, ok := msg["type"].(string)
-	if !ok {
-		return fmt.Errorf("invalid message type")
-	}
-	
-	roomID, _ := msg["room"].(string)
-	clientID, _ := msg["client_id"].(string)
-	fromClientID, _ := msg["from"].(string)
-	toClientID, _ := msg["to"].(string)
-
-
-	switch msgType {
-	case "join":
-		data, ok := msg["data"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("invalid join data")
+func (s *webrtcService) handleMessage(session *signalingSession, msg *entities.SignalingEnvelope) error {
+	if msg.Type == entities.MessageTypeJoin {
+		var payload entities.JoinPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		clientID, ok := data["client_id"].(string)
-		if !ok {
-			return fmt.Errorf("missing client_id")
+		if payload.ClientID == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "client_id is required")
 		}
-		role, ok := data["role"].(string)
-		if !ok {
-			return fmt.Errorf("missing role")
+		if payload.Role == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "role is required")
 		}
-		return s.HandleClientJoin(roomID, clientID, role, conn)
-
-	case "webrtc_offer":
-		data, ok := msg["data"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("invalid offer data")
+		if msg.Room == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "room is required")
 		}
-		sdp, ok := data["sdp"].(string)
-		if !ok {
-			return fmt.Errorf("missing SDP")
+
+		if session.clientID != "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidMessage, "connection has already joined room "+session.roomID)
 		}
-		offer := webrtc.SessionDescription{
-			Type: webrtc.SDPTypeOffer,
-			SDP:  sdp,
+
+		client, err := s.HandleClientJoin(msg.Room, &payload, session.claims, session.conn)
+		if err != nil {
+			return err
 		}
-		return s.HandleOffer(roomID, fromClientID, offer, toClientID)
+		session.client = client
+		session.roomID = client.RoomID
+		session.clientID = client.ID
+		session.role = client.Role
+		session.version = client.ProtocolVersion
+		return nil
+	}
 
-	case "webrtc_answer":
-		data, ok := msg["data"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("invalid answer data")
+	if session.clientID == "" {
+		return entities.NewSignalingError(entities.SignalingErrNotJoined, "join a room before sending "+msg.Type)
+	}
+	if sellerOnlyMessageTypes[msg.Type] && session.role != entities.RolePublisher {
+		return entities.NewSignalingError(entities.SignalingErrForbidden, msg.Type+" may only be sent by the room's seller")
+	}
+
+	roomID := session.roomID
+	clientID := session.clientID
+
+	switch msg.Type {
+	case entities.MessageTypeWebRTCOffer, entities.MessageTypeWebRTCAnswer:
+		var payload entities.SessionDescriptionPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		sdp, ok := data["sdp"].(string)
-		if !ok {
-			return fmt.Errorf("missing SDP")
+		if payload.SDP == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "sdp is required")
+		}
+		if msg.To == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "to is required")
+		}
+		if msg.Type == entities.MessageTypeWebRTCOffer {
+			offer := webrtc.SessionDescription{
+				Type: webrtc.SDPTypeOffer,
+				SDP:  payload.SDP,
+			}
+			return s.HandleOffer(roomID, clientID, offer, msg.To)
 		}
 		answer := webrtc.SessionDescription{
 			Type: webrtc.SDPTypeAnswer,
-			SDP:  sdp,
+			SDP:  payload.SDP,
 		}
-		return s.HandleAnswer(roomID, fromClientID, answer, toClientID)
+		return s.HandleAnswer(roomID, clientID, answer, msg.To)
 
-	case "webrtc_ice_candidate":
-		data, ok := msg["data"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("invalid ICE data")
+	case entities.MessageTypeWebRTCICECandidate:
+		var payload entities.ICECandidatePayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		
-		
-		// Simply forward the ICE candidate data as-is to maintain browser compatibility
-		return s.HandleICECandidate(roomID, fromClientID, data, toClientID)
+		if msg.To == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "to is required")
+		}
+		return s.HandleICECandidate(roomID, clientID, payload, msg.To)
 
-	case "chat":
-		message := entities.WebRTCMessage{
-			Type: "chat",
-			Data: msg["data"],
-			Room: roomID,
-			From: clientID,
+	case entities.MessageTypeChat:
+		var payload entities.ChatPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		return s.repo.BroadcastToRoom(roomID, message, "")
+		if payload.Message == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "message is required")
+		}
+		return s.broadcast(roomID, msg, payload, clientID, "")
 
-	case "reaction":
-		message := entities.WebRTCMessage{
-			Type: "reaction",
-			Data: msg["data"],
-			Room: roomID,
-			From: clientID,
+	case entities.MessageTypeReaction:
+		var payload entities.ReactionPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		return s.repo.BroadcastToRoom(roomID, message, "")
+		if payload.Emoji == "" {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "emoji is required")
+		}
+		return s.broadcast(roomID, msg, payload, clientID, "")
 
-	case "seller_live":
-		// Just broadcast that seller is live - no peer connection handling needed
-		message := entities.WebRTCMessage{
-			Type: "seller_live",
-			Data: msg["data"],
-			Room: roomID,
+	case entities.MessageTypeSellerLive, entities.MessageTypeSellerOffline:
+		var payload entities.SellerStatusPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		return s.repo.BroadcastToRoom(roomID, message, clientID)
+		return s.broadcast(roomID, msg, payload, "", clientID)
 
-	case "seller_offline":
-		message := entities.WebRTCMessage{
-			Type: "seller_offline",
-			Data: msg["data"],
-			Room: roomID,
+	case entities.MessageTypeProductPinned:
+		var payload entities.ProductPinnedPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
+		}
+		if payload.ProductID <= 0 {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "product_id is required")
 		}
-		return s.repo.BroadcastToRoom(roomID, message, clientID)
+		return s.broadcast(roomID, msg, payload, clientID, clientID)
 
-	case "product_pinned":
-		message := entities.WebRTCMessage{
-			Type: "product_pinned",
-			Data: msg["data"],
-			Room: roomID,
-			From: clientID,
+	case entities.MessageTypeProductUnpinned:
+		var payload entities.ProductUnpinnedPayload
+		if err := decodePayload(msg, &payload); err != nil {
+			return err
 		}
-		return s.repo.BroadcastToRoom(roomID, message, clientID)
+		if payload.ProductID <= 0 {
+			return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "product_id is required")
+		}
+		return s.broadcast(roomID, msg, payload, clientID, clientID)
+	}
 
-	case "product_unpinned":
-		message := entities.WebRTCMessage{
-			Type: "product_unpinned",
-			Data: msg["data"],
-			Room: roomID,
-			From: clientID,
+	return entities.NewSignalingError(entities.SignalingErrUnknownType, "unknown message type "+msg.Type)
+}
+
+// handleFrame queues a binary frame from the publisher for detection. The
+// result is pushed back on the same connection once it is ready.
+func (s *webrtcService) handleFrame(session *signalingSession, data []byte) error {
+	if session.clientID == "" {
+		return entities.NewSignalingError(entities.SignalingErrNotJoined, "join a room before sending frames")
+	}
+	if session.role != entities.RolePublisher {
+		return entities.NewSignalingError(entities.SignalingErrForbidden, "frames may only be sent by the room's seller")
+	}
+	if session.version < entities.FrameProtocolVersion {
+		return entities.NewSignalingError(entities.SignalingErrUnsupportedVersion,
+			fmt.Sprintf("frames need protocol version %d, the session uses %d", entities.FrameProtocolVersion, session.version))
+	}
+
+	frame, err := entities.ParseFrameMessage(data)
+	if err != nil {
+		return err
+	}
+
+	if session.frames == nil {
+		sellerID, ok := entities.SellerIDFromRoom(session.roomID)
+		if !ok {
+			return entities.NewSignalingError(entities.SignalingErrForbidden, "room "+session.roomID+" does not belong to a seller")
 		}
-		return s.repo.BroadcastToRoom(roomID, message, clientID)
+		session.frames = newFrameIngest(s.processFrame, session.client, sellerID, s.frameMaxAge)
+	}
+	session.frames.push(frame)
+	return nil
+}
 
-	default:
+// decodePayload strictly decodes the envelope data into the payload struct for
+// its type, so renamed or mistyped fields are reported instead of dropped.
+func decodePayload(msg *entities.SignalingEnvelope, payload interface{}) error {
+	if len(msg.Data) == 0 || string(msg.Data) == "null" {
+		return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "missing data for "+msg.Type)
 	}
 
+	decoder := json.NewDecoder(bytes.NewReader(msg.Data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return entities.NewSignalingError(entities.SignalingErrInvalidPayload, fmt.Sprintf("invalid %s data: %v", msg.Type, err))
+	}
 	return nil
 }
 
-func (s *webrtcService) HandleClientJoin(roomID, clientID, role string, conn *websocket.Conn) error {
+func (s *webrtcService) broadcast(roomID string, msg *entities.SignalingEnvelope, payload interface{}, from, excludeClientID string) error {
+	message := entities.WebRTCMessage{
+		ID:   msg.ID,
+		Type: msg.Type,
+		Data: payload,
+		Room: roomID,
+		From: from,
+	}
+	return s.repo.BroadcastToRoom(roomID, message, excludeClientID)
+}
+
+func (s *webrtcService) sendError(session *signalingSession, msg *entities.SignalingEnvelope, err error) {
+	sigErr, ok := err.(*entities.SignalingError)
+	if !ok {
+		sigErr = entities.NewSignalingError(entities.SignalingErrDeliveryFailed, err.Error())
+	}
+
+	session.writeJSON(entities.WebRTCMessage{
+		Type: entities.MessageTypeError,
+		Room: msg.Room,
+		Data: entities.ErrorPayload{
+			Code:        sigErr.Code,
+			Message:     sigErr.Message,
+			MessageID:   msg.ID,
+			MessageType: msg.Type,
+		},
+	})
+}
+
+// negotiateProtocolVersion picks the version used for a session. Clients that
+// predate versioning do not send one and are treated as version 1.
+func negotiateProtocolVersion(requested int) (int, error) {
+	if requested == 0 {
+		return entities.SignalingProtocolMinVersion, nil
+	}
+	if requested < entities.SignalingProtocolMinVersion {
+		return 0, entities.NewSignalingError(entities.SignalingErrUnsupportedVersion,
+			fmt.Sprintf("protocol version %d is not supported, minimum is %d", requested, entities.SignalingProtocolMinVersion))
+	}
+	if requested > entities.SignalingProtocolVersion {
+		return entities.SignalingProtocolVersion, nil
+	}
+	return requested, nil
+}
+
+func (s *webrtcService) HandleClientJoin(roomID string, join *entities.JoinPayload, claims *entities.StreamClaims, conn *websocket.Conn) (*entities.Client, error) {
+	version, err := negotiateProtocolVersion(join.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeJoin(roomID, join, claims); err != nil {
+		return nil, err
+	}
+
+	// The frame sampler joins in-process and would take over a connection
+	// that got to its ID first.
+	if join.ClientID == samplerClientID {
+		return nil, entities.NewSignalingError(entities.SignalingErrClientIDTaken, "client_id "+join.ClientID+" is reserved")
+	}
+
+	if existing := s.repo.GetClient(roomID, join.ClientID); existing != nil && existing.Conn != conn {
+		return nil, entities.NewSignalingError(entities.SignalingErrClientIDTaken, "client_id "+join.ClientID+" is already connected to this room")
+	}
 
 	// For pure signaling server, we don't create peer connections on backend
 	client := &entities.Client{
-		ID:            clientID,
+		ID:            join.ClientID,
 		Conn:          conn,
 		PeerConnection: nil, // No backend peer connection needed
-		Role:          role,
+		Role:          join.Role,
 		RoomID:        roomID,
+		ProtocolVersion: version,
 		ConnectedAt:   time.Now(),
 	}
 
@@ -242,70 +412,114 @@ func (s *webrtcService) HandleClientJoin(roomID, clientID, role string, conn *we
 
 	// Send joined message immediately
 	response := entities.WebRTCMessage{
-		Type: "joined",
-		Data: map[string]string{"status": "success", "client_id": clientID},
+		Type: entities.MessageTypeJoined,
+		Data: entities.JoinedPayload{
+			Status:          "success",
+			ClientID:        client.ID,
+			ProtocolVersion: version,
+		},
 		Room: roomID,
 	}
-	if err := conn.WriteJSON(response); err != nil {
-		return err
+	if err := client.WriteJSON(response); err != nil {
+		return nil, err
 	}
 
-	userJoinMsg := entities.WebRTCMessage{
-		Type: "user_joined",
-		Data: map[string]string{"client_id": clientID},
-		Room: roomID,
+	// The seller's dashboard is not a participant of the stream.
+	if client.Role != entities.RoleDashboard {
+		userJoinMsg := entities.WebRTCMessage{
+			Type: entities.MessageTypeUserJoined,
+			Data: entities.ClientPayload{ClientID: client.ID},
+			Room: roomID,
+		}
+		s.repo.BroadcastToRoom(roomID, userJoinMsg, client.ID)
 	}
-	s.repo.BroadcastToRoom(roomID, userJoinMsg, clientID)
 
 	// Update viewer count for livestream
-	if role == "viewer" {
+	if client.Role == "viewer" {
 		s.updateViewerCount(roomID)
 	}
 
-	return nil
-}
-
-// No longer needed - backend is pure signaling server
-
+	if client.Role == entities.RolePublisher && s.frameSampler != nil {
+		s.frameSampler.start(roomID, client.ID)
+	}
 
+	return client, nil
+}
 
 func (s *webrtcService) HandleOffer(roomID, fromClientID string, offer webrtc.SessionDescription, toClientID string) error {
 	// Forward the offer to the seller
 	message := entities.WebRTCMessage{
-		Type: "webrtc_offer",
-		Data: map[string]string{"sdp": offer.SDP},
+		Type: entities.MessageTypeWebRTCOffer,
+		Data: entities.SessionDescriptionPayload{Type: offer.Type.String(), SDP: offer.SDP},
 		Room: roomID,
 		From: fromClientID,
 		To:   toClientID,
 	}
 
-	return s.repo.SendToClient(roomID, toClientID, message)
+	return s.sendToClient(roomID, toClientID, message)
 }
 
 func (s *webrtcService) HandleAnswer(roomID, fromClientID string, answer webrtc.SessionDescription, toClientID string) error {
 	// Forward the answer to the target client
 	message := entities.WebRTCMessage{
-		Type: "webrtc_answer",
-		Data: map[string]string{"sdp": answer.SDP},
+		Type: entities.MessageTypeWebRTCAnswer,
+		Data: entities.SessionDescriptionPayload{Type: answer.Type.String(), SDP: answer.SDP},
 		Room: roomID,
 		From: fromClientID,
 		To:   toClientID,
 	}
 
-	return s.repo.SendToClient(roomID, toClientID, message)
+	return s.sendToClient(roomID, toClientID, message)
 }
 
-func (s *webrtcService) HandleICECandidate(roomID, fromClientID string, candidateData map[string]interface{}, toClientID string) error {
-
-	// Forward the ICE candidate data as-is to maintain browser compatibility
+func (s *webrtcService) HandleICECandidate(roomID, fromClientID string, candidate entities.ICECandidatePayload, toClientID string) error {
 	message := entities.WebRTCMessage{
-		Type: "webrtc_ice_candidate",
-		Data: candidateData,
+		Type: entities.MessageTypeWebRTCICECandidate,
+		Data: candidate,
 		Room: roomID,
 		From: fromClientID,
 		To:   toClientID,
 	}
 
+	return s.sendToClient(roomID, toClientID, message)
+}
+
+// authorizeJoin checks the requested role against the connection's token. Only
+// the seller owning a room may publish into it.
+func authorizeJoin(roomID string, join *entities.JoinPayload, claims *entities.StreamClaims) error {
+	if claims == nil {
+		return entities.NewSignalingError(entities.SignalingErrForbidden, "connection is not authenticated")
+	}
+
+	switch join.Role {
+	case entities.RolePublisher:
+		if claims.Role != entities.RolePublisher || claims.SellerID == 0 {
+			return entities.NewSignalingError(entities.SignalingErrForbidden, "token does not allow the publisher role")
+		}
+		if entities.SellerRoomID(claims.SellerID) != roomID {
+			return entities.NewSignalingError(entities.SignalingErrForbidden, "only the owning seller may publish to room "+roomID)
+		}
+	case entities.RoleDashboard:
+		if claims.Role != entities.RoleDashboard || claims.SellerID == 0 {
+			return entities.NewSignalingError(entities.SignalingErrForbidden, "token does not allow the dashboard role")
+		}
+		if entities.SellerRoomID(claims.SellerID) != roomID {
+			return entities.NewSignalingError(entities.SignalingErrForbidden, "only the owning seller may watch room "+roomID+" from the dashboard")
+		}
+	case entities.RoleViewer:
+	default:
+		return entities.NewSignalingError(entities.SignalingErrInvalidPayload, "unknown role "+join.Role)
+	}
+
+	return nil
+}
+
+// sendToClient delivers a targeted message, reporting unknown recipients to
+// the sender instead of dropping the message silently.
+func (s *webrtcService) sendToClient(roomID, toClientID string, message entities.WebRTCMessage) error {
+	if s.repo.GetClient(roomID, toClientID) == nil {
+		return entities.NewSignalingError(entities.SignalingErrDeliveryFailed, "client "+toClientID+" is not in room "+roomID)
+	}
 	return s.repo.SendToClient(roomID, toClientID, message)
 }
 
@@ -316,6 +530,13 @@ func (s *webrtcService) CreatePeerConnection(role string) (*webrtc.PeerConnectio
 	}
 
 	mediaEngine := &webrtc.MediaEngine{}
+	if role == entities.RoleSampler {
+		// The frame sampler can only decode VP8, so it offers nothing else.
+		if err := registerSamplerCodecs(mediaEngine); err != nil {
+			return nil, err
+		}
+		return newPeerConnection(mediaEngine, config)
+	}
 	if err := mediaEngine.RegisterDefaultCodecs(); err != nil {
 		return nil, err
 	}
@@ -332,6 +553,23 @@ func (s *webrtcService) CreatePeerConnection(role string) (*webrtc.PeerConnectio
 	}, webrtc.RTPCodecTypeVideo); err != nil {
 	}
 
+	return newPeerConnection(mediaEngine, config)
+}
+
+func registerSamplerCodecs(mediaEngine *webrtc.MediaEngine) error {
+	if err := mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000},
+		PayloadType:        96,
+	}, webrtc.RTPCodecTypeVideo); err != nil {
+		return err
+	}
+	return mediaEngine.RegisterCodec(webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2, SDPFmtpLine: "minptime=10;useinbandfec=1"},
+		PayloadType:        111,
+	}, webrtc.RTPCodecTypeAudio)
+}
+
+func newPeerConnection(mediaEngine *webrtc.MediaEngine, config webrtc.Configuration) (*webrtc.PeerConnection, error) {
 	settingEngine := webrtc.SettingEngine{}
 	
 	// Set UDP port range for better NAT traversal
@@ -366,12 +604,14 @@ func (s *webrtcService) cleanupClient(roomID, clientID string) {
 		return
 	}
 	
-	userLeftMsg := entities.WebRTCMessage{
-		Type: "user_left",
-		Data: map[string]string{"client_id": clientID},
-		Room: roomID,
+	if client.Role != entities.RoleDashboard {
+		userLeftMsg := entities.WebRTCMessage{
+			Type: entities.MessageTypeUserLeft,
+			Data: entities.ClientPayload{ClientID: clientID},
+			Room: roomID,
+		}
+		s.repo.BroadcastToRoom(roomID, userLeftMsg, clientID)
 	}
-	s.repo.BroadcastToRoom(roomID, userLeftMsg, clientID)
 
 	s.repo.RemoveClientFromRoom(roomID, clientID)
 	
@@ -379,6 +619,10 @@ func (s *webrtcService) cleanupClient(roomID, clientID string) {
 	if client.Role == "viewer" {
 		s.updateViewerCount(roomID)
 	}
+
+	if client.Role == entities.RolePublisher && s.frameSampler != nil {
+		s.frameSampler.stop(roomID)
+	}
 	
 	room := s.repo.GetRoom(roomID)
 	if room != nil && len(room.Clients) == 0 {
@@ -413,7 +657,7 @@ func (s *webrtcService) GetRoomStats(roomID string) map[string]interface{} {
 	for _, client := range room.Clients {
 		if client.Role == "publisher" {
 			stats["publishers"] = stats["publishers"].(int) + 1
-		} else {
+		} else if client.Role == "viewer" {
 			stats["viewers"] = stats["viewers"].(int) + 1
 		}
 	}
@@ -422,6 +666,11 @@ func (s *webrtcService) GetRoomStats(roomID string) map[string]interface{} {
 }
 
 func (s *webrtcService) updateViewerCount(roomID string) {
+	sellerID, ok := entities.SellerIDFromRoom(roomID)
+	if !ok {
+		return
+	}
+
 	room := s.repo.GetRoom(roomID)
 	if room == nil {
 		return
@@ -437,7 +686,7 @@ func (s *webrtcService) updateViewerCount(roomID string) {
 	room.Mutex.RUnlock()
 	
 	// Update viewer count in database
-	if err := s.liveStreamRepo.UpdateViewerCount(roomID, viewerCount); err != nil {
+	if err := s.liveStreamRepo.UpdateViewerCount(sellerID, viewerCount); err != nil {
 	}
 	
 }
\ No newline at end of file
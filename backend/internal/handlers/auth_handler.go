@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService services.AuthService
+}
+
+func NewAuthHandler(authService services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req entities.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.Register(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, result)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req entities.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, result)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req entities.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, result)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(c.Request.Context(), middleware.GetPrincipal(c)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *AuthHandler) Me(c *gin.Context) {
+	user, err := h.authService.GetCurrentUser(c.Request.Context(), middleware.GetPrincipal(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, user)
+}
+
+func (h *AuthHandler) BecomeSeller(c *gin.Context) {
+	var req entities.BecomeSellerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authService.BecomeSeller(c.Request.Context(), middleware.GetPrincipal(c), &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, result)
+}
+
+func (h *AuthHandler) IssueStreamToken(c *gin.Context) {
+	var req entities.StreamTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.authService.IssueStreamToken(c.Request.Context(), middleware.GetPrincipal(c), &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, token)
+}
+
+// errorStatus maps domain errors to HTTP status codes, defaulting to 500.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		return 404
+	case errors.Is(err, entities.ErrForbidden):
+		return 403
+	case errors.Is(err, entities.ErrConflict), errors.Is(err, entities.ErrInsufficientStock):
+		return 409
+	case errors.Is(err, entities.ErrInvalidCredentials):
+		return 401
+	case errors.Is(err, entities.ErrInvalidInput), errors.Is(err, entities.ErrInvalidSignature):
+		return 400
+	case errors.Is(err, entities.ErrMLUnavailable):
+		return 503
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AutoPinHandler struct {
+	autoPinService services.AutoPinService
+}
+
+func NewAutoPinHandler(autoPinService services.AutoPinService) *AutoPinHandler {
+	return &AutoPinHandler{
+		autoPinService: autoPinService,
+	}
+}
+
+func (h *AutoPinHandler) GetSettings(c *gin.Context) {
+	settings, err := h.autoPinService.GetSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, settings)
+}
+
+func (h *AutoPinHandler) UpdateSettings(c *gin.Context) {
+	var req entities.AutoPinSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.autoPinService.UpdateSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, settings)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CartHandler struct {
+	cartService services.CartService
+}
+
+func NewCartHandler(cartService services.CartService) *CartHandler {
+	return &CartHandler{
+		cartService: cartService,
+	}
+}
+
+func (h *CartHandler) GetCart(c *gin.Context) {
+	cart, err := h.cartService.GetCart(c.Request.Context(), middleware.GetPrincipal(c).UserID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, cart)
+}
+
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req entities.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := h.cartService.AddItem(c.Request.Context(), middleware.GetPrincipal(c).UserID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, cart)
+}
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	var req entities.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := h.cartService.UpdateItem(c.Request.Context(), middleware.GetPrincipal(c).UserID, itemID, req.Quantity)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, cart)
+}
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	cart, err := h.cartService.RemoveItem(c.Request.Context(), middleware.GetPrincipal(c).UserID, itemID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, cart)
+}
+
+func (h *CartHandler) Checkout(c *gin.Context) {
+	orders, err := h.cartService.Checkout(c.Request.Context(), middleware.GetPrincipal(c).UserID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, gin.H{"orders": orders})
+}
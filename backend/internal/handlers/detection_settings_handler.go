@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DetectionSettingsHandler struct {
+	settingsService services.DetectionSettingsService
+}
+
+func NewDetectionSettingsHandler(settingsService services.DetectionSettingsService) *DetectionSettingsHandler {
+	return &DetectionSettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+func (h *DetectionSettingsHandler) GetSettings(c *gin.Context) {
+	settings, err := h.settingsService.GetSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, settings)
+}
+
+func (h *DetectionSettingsHandler) UpdateSettings(c *gin.Context) {
+	var req entities.DetectionSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, settings)
+}
+
+func (h *DetectionSettingsHandler) ResetSettings(c *gin.Context) {
+	settings, err := h.settingsService.ResetSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, settings)
+}
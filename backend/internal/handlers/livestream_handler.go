@@ -3,7 +3,9 @@ package handlers
 import (
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,6 +29,7 @@ func (h *LiveStreamHandler) StartLiveStream(c *gin.Context) {
 		})
 		return
 	}
+	req.SellerID = middleware.GetPrincipal(c).SellerID
 
 	stream, err := h.liveStreamService.StartLiveStream(&req)
 	if err != nil {
@@ -45,11 +48,11 @@ func (h *LiveStreamHandler) StartLiveStream(c *gin.Context) {
 }
 
 func (h *LiveStreamHandler) EndLiveStream(c *gin.Context) {
-	sellerID := c.Param("seller_id")
-	if sellerID == "" {
-		c.JSON(http.StatusBadRequest, entities.LiveStreamResponse{
+	sellerID := middleware.GetPrincipal(c).SellerID
+	if c.Param("seller_id") != strconv.Itoa(sellerID) {
+		c.JSON(http.StatusForbidden, entities.LiveStreamResponse{
 			Success: false,
-			Message: "Seller ID is required",
+			Message: "Cannot end another seller's livestream",
 		})
 		return
 	}
@@ -69,10 +72,31 @@ func (h *LiveStreamHandler) EndLiveStream(c *gin.Context) {
 	})
 }
 
+func (h *LiveStreamHandler) GetLiveStreams(c *gin.Context) {
+	h.listLiveStreams(c, false, "Livestreams retrieved successfully")
+}
+
 func (h *LiveStreamHandler) GetActiveLiveStreams(c *gin.Context) {
-	streams, err := h.liveStreamService.GetActiveLiveStreams()
+	h.listLiveStreams(c, true, "Active livestreams retrieved successfully")
+}
+
+func (h *LiveStreamHandler) listLiveStreams(c *gin.Context, liveOnly bool, message string) {
+	var filter entities.LiveStreamFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, entities.LiveStreamListResponse{
+			Success: false,
+			Message: "Invalid query: " + err.Error(),
+		})
+		return
+	}
+	if liveOnly {
+		isLive := true
+		filter.IsLive = &isLive
+	}
+
+	page, err := h.liveStreamService.ListLiveStreams(&filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, entities.LiveStreamListResponse{
+		c.JSON(errorStatus(err), entities.LiveStreamListResponse{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -80,18 +104,20 @@ func (h *LiveStreamHandler) GetActiveLiveStreams(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, entities.LiveStreamListResponse{
-		Success: true,
-		Data:    streams,
-		Message: "Active livestreams retrieved successfully",
+		Success:    true,
+		Data:       page.Data,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+		Message:    message,
 	})
 }
 
 func (h *LiveStreamHandler) GetLiveStreamBySellerID(c *gin.Context) {
-	sellerID := c.Param("seller_id")
-	if sellerID == "" {
+	sellerID, err := strconv.Atoi(c.Param("seller_id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, entities.LiveStreamResponse{
 			Success: false,
-			Message: "Seller ID is required",
+			Message: "Invalid seller ID",
 		})
 		return
 	}
@@ -110,4 +136,26 @@ func (h *LiveStreamHandler) GetLiveStreamBySellerID(c *gin.Context) {
 		Data:    stream,
 		Message: "Livestream retrieved successfully",
 	})
-}
\ No newline at end of file
+}
+
+func (h *LiveStreamHandler) GetTimeline(c *gin.Context) {
+	liveStreamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid livestream ID"})
+		return
+	}
+
+	var params entities.TimelineParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeline, err := h.liveStreamService.GetTimeline(c.Request.Context(), liveStreamID, &params)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler struct {
+	orderService services.OrderService
+}
+
+func NewOrderHandler(orderService services.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+func (h *OrderHandler) GetOrders(c *gin.Context) {
+	orders, err := h.orderService.ListBuyerOrders(c.Request.Context(), middleware.GetPrincipal(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, orders)
+}
+
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), middleware.GetPrincipal(c), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	order, err := h.orderService.CancelOrder(c.Request.Context(), middleware.GetPrincipal(c), id, req.Note)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+func (h *OrderHandler) GetSellerOrders(c *gin.Context) {
+	orders, err := h.orderService.ListSellerOrders(c.Request.Context(), middleware.GetPrincipal(c), c.Query("status"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, orders)
+}
+
+func (h *OrderHandler) TransitionOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req entities.OrderTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.orderService.TransitionOrder(c.Request.Context(), middleware.GetPrincipal(c), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
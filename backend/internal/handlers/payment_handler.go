@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"io"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodySize bounds how much of a webhook request is read before the
+// signature is checked.
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+}
+
+func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+func (h *PaymentHandler) PayOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	payment, err := h.paymentService.PayOrder(c.Request.Context(), middleware.GetPrincipal(c), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, payment)
+}
+
+func (h *PaymentHandler) RefundOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.paymentService.RefundOrder(c.Request.Context(), middleware.GetPrincipal(c), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, order)
+}
+
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	processed, err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), payload, c.Request.Header)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !processed {
+		c.JSON(200, gin.H{"message": "Event already processed"})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Event processed"})
+}
@@ -3,28 +3,52 @@ package handlers
 import (
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ProductHandler struct {
-	productService services.ProductService
+	productService   services.ProductService
+	inventoryService services.InventoryService
 }
 
-func NewProductHandler(productService services.ProductService) *ProductHandler {
+func NewProductHandler(productService services.ProductService, inventoryService services.InventoryService) *ProductHandler {
 	return &ProductHandler{
-		productService: productService,
+		productService:   productService,
+		inventoryService: inventoryService,
 	}
 }
 
 func (h *ProductHandler) GetProducts(c *gin.Context) {
-	products, err := h.productService.GetProducts(c.Request.Context())
+	var filter entities.ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.productService.ListProducts(c.Request.Context(), &filter)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(200, products)
+	c.JSON(200, page)
+}
+
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	var params entities.ProductSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.productService.SearchProducts(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, result)
 }
 
 func (h *ProductHandler) GetProduct(c *gin.Context) {
@@ -49,12 +73,19 @@ func (h *ProductHandler) GetProductsBySeller(c *gin.Context) {
 		return
 	}
 
-	products, err := h.productService.GetProductsBySellerID(c.Request.Context(), sellerID)
+	var filter entities.ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	filter.SellerID = &sellerID
+
+	page, err := h.productService.ListProducts(c.Request.Context(), &filter)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(200, products)
+	c.JSON(200, page)
 }
 
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
@@ -63,7 +94,6 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	product.Name = c.PostForm("name")
 	product.Description = c.PostForm("description")
 	priceStr := c.PostForm("price")
-	sellerIDStr := c.PostForm("seller_id")
 	
 	if product.Name == "" {
 		c.JSON(400, gin.H{"error": "Name is required"})
@@ -80,16 +110,16 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 	product.Price = price
-	
-	sellerID := 1
-	if sellerIDStr != "" {
-		sellerID, err = strconv.Atoi(sellerIDStr)
-		if err != nil {
-			c.JSON(400, gin.H{"error": "Invalid seller_id"})
+	product.SellerID = middleware.GetPrincipal(c).SellerID
+
+	if stockStr := c.PostForm("stock"); stockStr != "" {
+		stock, err := strconv.Atoi(stockStr)
+		if err != nil || stock < 0 {
+			c.JSON(400, gin.H{"error": "Invalid stock"})
 			return
 		}
+		product.Stock = stock
 	}
-	product.SellerID = sellerID
 
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -121,8 +151,8 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	}
 
 	product.ID = id
-	if err := h.productService.UpdateProduct(c.Request.Context(), &product); err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+	if err := h.productService.UpdateProduct(c.Request.Context(), middleware.GetPrincipal(c).SellerID, &product); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -136,8 +166,8 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.productService.DeleteProduct(c.Request.Context(), id); err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+	if err := h.productService.DeleteProduct(c.Request.Context(), middleware.GetPrincipal(c).SellerID, id); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -163,9 +193,9 @@ func (h *ProductHandler) AddProductImages(c *gin.Context) {
 		return
 	}
 
-	addedImages, err := h.productService.AddProductImages(c.Request.Context(), id, files)
+	addedImages, err := h.productService.AddProductImages(c.Request.Context(), middleware.GetPrincipal(c).SellerID, id, files)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -179,13 +209,13 @@ func (h *ProductHandler) TrainProduct(c *gin.Context) {
 		return
 	}
 
-	result, err := h.productService.TrainProductModel(c.Request.Context(), id)
+	job, err := h.productService.TrainProductModel(c.Request.Context(), middleware.GetPrincipal(c).SellerID, id)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, result)
+	c.JSON(202, job)
 }
 
 func (h *ProductHandler) PredictProduct(c *gin.Context) {
@@ -203,7 +233,7 @@ func (h *ProductHandler) PredictProduct(c *gin.Context) {
 
 	result, err := h.productService.PredictProduct(c.Request.Context(), id, file)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -218,16 +248,17 @@ func (h *ProductHandler) PinProduct(c *gin.Context) {
 	}
 
 	var pinData struct {
-		SellerID        int     `json:"seller_id"`
 		SimilarityScore float64 `json:"similarity_score"`
+		VariantID       *int    `json:"variant_id"`
 	}
 	if err := c.ShouldBindJSON(&pinData); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.productService.PinProduct(c.Request.Context(), productID, pinData.SellerID, pinData.SimilarityScore); err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+	sellerID := middleware.GetPrincipal(c).SellerID
+	if err := h.productService.PinProduct(c.Request.Context(), productID, pinData.VariantID, sellerID, pinData.SimilarityScore); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -241,11 +272,7 @@ func (h *ProductHandler) UnpinProduct(c *gin.Context) {
 		return
 	}
 
-	sellerID := c.Query("seller_id")
-	if sellerID == "" {
-		c.JSON(400, gin.H{"error": "seller_id is required"})
-		return
-	}
+	sellerID := middleware.GetPrincipal(c).SellerID
 
 	if err := h.productService.UnpinProduct(c.Request.Context(), productID, sellerID); err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
@@ -256,7 +283,11 @@ func (h *ProductHandler) UnpinProduct(c *gin.Context) {
 }
 
 func (h *ProductHandler) GetPinnedProducts(c *gin.Context) {
-	sellerID := c.Param("seller_id")
+	sellerID, err := strconv.Atoi(c.Param("seller_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid seller ID"})
+		return
+	}
 
 	products, err := h.productService.GetPinnedProducts(c.Request.Context(), sellerID)
 	if err != nil {
@@ -268,7 +299,11 @@ func (h *ProductHandler) GetPinnedProducts(c *gin.Context) {
 }
 
 func (h *ProductHandler) UnpinAllProducts(c *gin.Context) {
-	sellerID := c.Param("seller_id")
+	sellerID := middleware.GetPrincipal(c).SellerID
+	if c.Param("seller_id") != strconv.Itoa(sellerID) {
+		c.JSON(403, gin.H{"error": "Cannot unpin products of another seller"})
+		return
+	}
 
 	rowsAffected, err := h.productService.UnpinAllProducts(c.Request.Context(), sellerID)
 	if err != nil {
@@ -283,28 +318,68 @@ func (h *ProductHandler) UnpinAllProducts(c *gin.Context) {
 }
 
 func (h *ProductHandler) TrainAllSellers(c *gin.Context) {
-	sellerID := c.Query("seller_id")
-	if sellerID == "" {
-		sellerID = "1"
-	}
+	sellerID := middleware.GetPrincipal(c).SellerID
+	fineTune, _ := strconv.ParseBool(c.Query("fine_tune"))
 
-	result, err := h.productService.TrainAllSellers(c.Request.Context(), sellerID)
+	job, err := h.productService.TrainAllSellers(c.Request.Context(), sellerID, fineTune)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, result)
+	c.JSON(202, job)
 }
 
 func (h *ProductHandler) GetTrainingStatus(c *gin.Context) {
-	sellerID := c.Param("seller_id")
+	sellerID, err := strconv.Atoi(c.Param("seller_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid seller ID"})
+		return
+	}
 
 	status, err := h.productService.GetTrainingStatus(c.Request.Context(), sellerID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(200, status)
-}
\ No newline at end of file
+}
+
+func (h *ProductHandler) GetStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	level, err := h.inventoryService.GetStock(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, level)
+}
+
+func (h *ProductHandler) UpdateStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entities.UpdateStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	level, err := h.inventoryService.SetStock(c.Request.Context(), middleware.GetPrincipal(c).SellerID, id, *req.Stock)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, level)
+}
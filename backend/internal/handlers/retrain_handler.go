@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RetrainHandler struct {
+	retrainService services.RetrainService
+}
+
+func NewRetrainHandler(retrainService services.RetrainService) *RetrainHandler {
+	return &RetrainHandler{
+		retrainService: retrainService,
+	}
+}
+
+func (h *RetrainHandler) GetSettings(c *gin.Context) {
+	state, err := h.retrainService.GetSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, state)
+}
+
+func (h *RetrainHandler) UpdateSettings(c *gin.Context) {
+	var req entities.RetrainSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := h.retrainService.UpdateSettings(c.Request.Context(), middleware.GetPrincipal(c).SellerID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, state)
+}
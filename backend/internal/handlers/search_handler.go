@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService services.SearchService
+}
+
+func NewSearchHandler(searchService services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) VisualSearch(c *gin.Context) {
+	var params entities.VisualSearchParams
+	if err := c.ShouldBind(&params); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(400, gin.H{"error": "No image file provided"})
+		return
+	}
+
+	result, err := h.searchService.VisualSearch(c.Request.Context(), &params, file)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, result)
+}
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,11 +20,7 @@ func NewStreamHandler(streamService services.StreamService) *StreamHandler {
 }
 
 func (h *StreamHandler) ProcessStreamFrame(c *gin.Context) {
-	sellerID := c.Query("seller_id")
-	if sellerID == "" {
-		c.JSON(400, gin.H{"error": "seller_id is required"})
-		return
-	}
+	sellerID := middleware.GetPrincipal(c).SellerID
 
 	file, err := c.FormFile("frame")
 	if err != nil {
@@ -31,7 +30,38 @@ func (h *StreamHandler) ProcessStreamFrame(c *gin.Context) {
 
 	result, err := h.streamService.ProcessStreamFrame(c.Request.Context(), sellerID, file)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, result)
+}
+
+// ProcessStreamFrames takes a burst of frames sent as repeated "frames"
+// fields, optionally with a "captured_at" field in Unix milliseconds for
+// each, and returns their results in the same order.
+func (h *StreamHandler) ProcessStreamFrames(c *gin.Context) {
+	sellerID := middleware.GetPrincipal(c).SellerID
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["frames"]) == 0 {
+		c.JSON(400, gin.H{"error": "No frame files provided"})
+		return
+	}
+
+	var capturedAt []time.Time
+	for _, value := range form.Value["captured_at"] {
+		millis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid captured_at"})
+			return
+		}
+		capturedAt = append(capturedAt, time.UnixMilli(millis))
+	}
+
+	result, err := h.streamService.ProcessStreamFrames(c.Request.Context(), sellerID, form.File["frames"], capturedAt)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -39,9 +69,9 @@ func (h *StreamHandler) ProcessStreamFrame(c *gin.Context) {
 }
 
 func (h *StreamHandler) PredictFrame(c *gin.Context) {
-	sellerID := c.Query("seller_id")
-	if sellerID == "" {
-		c.JSON(400, gin.H{"error": "seller_id is required"})
+	sellerID, err := strconv.Atoi(c.Query("seller_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid seller_id"})
 		return
 	}
 
@@ -53,7 +83,7 @@ func (h *StreamHandler) PredictFrame(c *gin.Context) {
 
 	result, err := h.streamService.PredictFrame(c.Request.Context(), sellerID, file)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
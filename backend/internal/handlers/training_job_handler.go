@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrainingJobHandler struct {
+	trainingService services.TrainingJobService
+}
+
+func NewTrainingJobHandler(trainingService services.TrainingJobService) *TrainingJobHandler {
+	return &TrainingJobHandler{
+		trainingService: trainingService,
+	}
+}
+
+func (h *TrainingJobHandler) CreateJob(c *gin.Context) {
+	var req entities.TrainingJobRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	job, err := h.trainingService.Enqueue(c.Request.Context(), middleware.GetPrincipal(c).SellerID, nil, req.FineTune)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(202, job)
+}
+
+func (h *TrainingJobHandler) ListJobs(c *gin.Context) {
+	var filter entities.TrainingJobFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.trainingService.ListJobs(c.Request.Context(), middleware.GetPrincipal(c).SellerID, &filter)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, page)
+}
+
+func (h *TrainingJobHandler) GetJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid training job ID"})
+		return
+	}
+
+	job, err := h.trainingService.GetJob(c.Request.Context(), middleware.GetPrincipal(c).SellerID, id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, job)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariantHandler struct {
+	variantService   services.VariantService
+	inventoryService services.InventoryService
+}
+
+func NewVariantHandler(variantService services.VariantService, inventoryService services.InventoryService) *VariantHandler {
+	return &VariantHandler{
+		variantService:   variantService,
+		inventoryService: inventoryService,
+	}
+}
+
+func (h *VariantHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	variants, err := h.variantService.GetVariants(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, variants)
+}
+
+func (h *VariantHandler) SetOptions(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entities.ProductOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	options, err := h.variantService.SetOptions(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, req.Options)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"options": options})
+}
+
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req entities.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := h.variantService.CreateVariant(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(201, variant)
+}
+
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+
+	var req entities.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := h.variantService.UpdateVariant(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, variantID, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, variant)
+}
+
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, variantID); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Variant deleted successfully"})
+}
+
+func (h *VariantHandler) UpdateVariantStock(c *gin.Context) {
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+
+	var req entities.UpdateStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	level, err := h.inventoryService.SetVariantStock(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, variantID, *req.Stock)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, level)
+}
+
+func (h *VariantHandler) AddVariantImages(c *gin.Context) {
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Failed to parse multipart form"})
+		return
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		c.JSON(400, gin.H{"error": "No images provided"})
+		return
+	}
+
+	addedImages, err := h.variantService.AddVariantImages(c.Request.Context(), middleware.GetPrincipal(c).SellerID, productID, variantID, files)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Images added successfully", "images": addedImages})
+}
+
+// variantParams parses the product and variant IDs from the path, writing a
+// 400 response when either is malformed.
+func variantParams(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid product ID"})
+		return 0, 0, false
+	}
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid variant ID"})
+		return 0, 0, false
+	}
+	return productID, variantID, true
+}
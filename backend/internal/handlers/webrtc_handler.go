@@ -1,50 +1,73 @@
 package handlers
 
 import (
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/services"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
+var errMissingToken = errors.New("stream token is required")
+
 type WebRTCHandler struct {
 	webrtcService services.WebRTCService
+	authService   services.AuthService
 	upgrader      websocket.Upgrader
 }
 
-func NewWebRTCHandler(webrtcService services.WebRTCService) *WebRTCHandler {
+func NewWebRTCHandler(webrtcService services.WebRTCService, authService services.AuthService) *WebRTCHandler {
+	allowedOrigins := parseAllowedOrigins(os.Getenv("ALLOWED_ORIGINS"))
+
 	return &WebRTCHandler{
 		webrtcService: webrtcService,
+		authService:   authService,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
-				return true
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					// Non-browser clients do not send an Origin header
+					return true
+				}
+				return allowedOrigins["*"] || allowedOrigins[origin]
 			},
 		},
 	}
 }
 
-func (h *WebRTCHandler) HandleWebRTCWebSocket(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	c.Header("Access-Control-Allow-Headers", "*")
-
-	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		return
+func parseAllowedOrigins(value string) map[string]bool {
+	if value == "" {
+		value = "http://localhost:3000,http://localhost:5173"
 	}
-	defer conn.Close()
 
-	if err := h.webrtcService.HandleWebSocketConnection(conn); err != nil {
+	origins := make(map[string]bool)
+	for _, origin := range strings.Split(value, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins[strings.TrimSuffix(origin, "/")] = true
+		}
 	}
+	return origins
+}
+
+func (h *WebRTCHandler) HandleWebRTCWebSocket(c *gin.Context) {
+	h.serveWebSocket(c)
 }
 
 func (h *WebRTCHandler) HandleLiveStreamWebSocket(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	c.Header("Access-Control-Allow-Headers", "*")
+	h.serveWebSocket(c)
+}
+
+func (h *WebRTCHandler) serveWebSocket(c *gin.Context) {
+	claims, err := h.authenticate(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
 
 	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -52,10 +75,25 @@ func (h *WebRTCHandler) HandleLiveStreamWebSocket(c *gin.Context) {
 	}
 	defer conn.Close()
 
-	if err := h.webrtcService.HandleWebSocketConnection(conn); err != nil {
+	if err := h.webrtcService.HandleWebSocketConnection(conn, claims); err != nil {
 	}
 }
 
+// authenticate reads the stream token from the query string, since browsers
+// cannot set headers on WebSocket requests, or from a bearer Authorization
+// header for other clients.
+func (h *WebRTCHandler) authenticate(c *gin.Context) (*entities.StreamClaims, error) {
+	token := c.Query("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	if token == "" {
+		return nil, errMissingToken
+	}
+
+	return h.authService.VerifyStreamToken(token)
+}
+
 func (h *WebRTCHandler) GetWebRTCConfig(c *gin.Context) {
 	serverPublicIP := os.Getenv("SERVER_PUBLIC_IP")
 	if serverPublicIP == "" {
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Each token kind is signed with the same key but carries its own audience,
+// so a refresh or stream token can never be replayed as an access token.
+const (
+	streamTokenAudience  = "stream"
+	accessTokenAudience  = "access"
+	refreshTokenAudience = "refresh"
+)
+
+type streamTokenClaims struct {
+	Role     string `json:"role"`
+	SellerID int    `json:"seller_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type accessTokenClaims struct {
+	Email    string `json:"email"`
+	SellerID int    `json:"seller_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type jwtTokenRepository struct {
+	secret []byte
+}
+
+func NewJWTTokenRepository() repositories.TokenRepository {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			log.Fatal("Failed to generate JWT secret:", err)
+		}
+		secret = hex.EncodeToString(buf)
+		log.Println("JWT_SECRET is not set, using a random secret; tokens will not survive a restart")
+	}
+	return &jwtTokenRepository{secret: []byte(secret)}
+}
+
+func (r *jwtTokenRepository) IssueStreamToken(claims *entities.StreamClaims) (string, error) {
+	return r.sign(streamTokenClaims{
+		Role:             claims.Role,
+		SellerID:         claims.SellerID,
+		RegisteredClaims: registeredClaims(claims.Subject, streamTokenAudience, "", claims.ExpiresAt),
+	})
+}
+
+func (r *jwtTokenRepository) VerifyStreamToken(tokenString string) (*entities.StreamClaims, error) {
+	var claims streamTokenClaims
+	if err := r.parse(tokenString, streamTokenAudience, &claims); err != nil {
+		return nil, fmt.Errorf("invalid stream token: %w", err)
+	}
+
+	return &entities.StreamClaims{
+		Subject:   claims.Subject,
+		Role:      claims.Role,
+		SellerID:  claims.SellerID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (r *jwtTokenRepository) IssueAccessToken(principal *entities.Principal, expiresAt time.Time) (string, error) {
+	return r.sign(accessTokenClaims{
+		Email:            principal.Email,
+		SellerID:         principal.SellerID,
+		RegisteredClaims: registeredClaims(strconv.Itoa(principal.UserID), accessTokenAudience, "", expiresAt),
+	})
+}
+
+func (r *jwtTokenRepository) VerifyAccessToken(tokenString string) (*entities.Principal, error) {
+	var claims accessTokenClaims
+	if err := r.parse(tokenString, accessTokenAudience, &claims); err != nil {
+		return nil, fmt.Errorf("invalid access token: %w", err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token subject: %w", err)
+	}
+
+	return &entities.Principal{
+		UserID:   userID,
+		Email:    claims.Email,
+		SellerID: claims.SellerID,
+	}, nil
+}
+
+func (r *jwtTokenRepository) IssueRefreshToken(claims *entities.RefreshClaims) (string, error) {
+	return r.sign(registeredClaims(strconv.Itoa(claims.UserID), refreshTokenAudience, claims.TokenID, claims.ExpiresAt))
+}
+
+func (r *jwtTokenRepository) VerifyRefreshToken(tokenString string) (*entities.RefreshClaims, error) {
+	var claims jwt.RegisteredClaims
+	if err := r.parse(tokenString, refreshTokenAudience, &claims); err != nil {
+		return nil, fmt.Errorf("invalid refresh token: %w", err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || claims.ID == "" {
+		return nil, fmt.Errorf("invalid refresh token claims")
+	}
+
+	return &entities.RefreshClaims{
+		UserID:    userID,
+		TokenID:   claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func registeredClaims(subject, audience, id string, expiresAt time.Time) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		ID:        id,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+}
+
+func (r *jwtTokenRepository) sign(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(r.secret)
+}
+
+func (r *jwtTokenRepository) parse(tokenString, audience string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, r.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
+	return err
+}
+
+func (r *jwtTokenRepository) keyFunc(*jwt.Token) (interface{}, error) {
+	return r.secret, nil
+}
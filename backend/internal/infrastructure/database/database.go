@@ -4,87 +4,92 @@ import (
 	"context"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var DB *pgx.Conn
+var DB *pgxpool.Pool
 
-func InitDatabase() *pgx.Conn {
+// InitDatabase connects to Postgres and applies any pending migrations.
+func InitDatabase() *pgxpool.Pool {
+	Connect()
+
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		log.Fatal("Migration load error:", err)
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatal("Migration error:", err)
+	}
+	for _, m := range applied {
+		log.Printf("Applied migration %d_%s", m.Version, m.Name)
+	}
+
+	return DB
+}
+
+// Connect opens the connection pool without touching the schema. Pool size,
+// health checks and the per-statement timeout are configured through
+// DB_MAX_CONNS, DB_MIN_CONNS, DB_HEALTH_CHECK_PERIOD, DB_MAX_CONN_LIFETIME,
+// DB_MAX_CONN_IDLE_TIME and DB_STATEMENT_TIMEOUT.
+func Connect() *pgxpool.Pool {
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
 		connStr = "host=localhost port=5432 user=postgres password=postgres dbname=livecommerce sslmode=disable"
 	}
-	
-	
-	config, err := pgx.ParseConfig(connStr)
+
+	config, err := pgxpool.ParseConfig(connStr)
 	if err != nil {
 		log.Fatal("Config parse error:", err)
 	}
-	
-	config.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
-	
-	DB, err = pgx.ConnectConfig(context.Background(), config)
+
+	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	config.MaxConns = int32(envInt("DB_MAX_CONNS", 20))
+	config.MinConns = int32(envInt("DB_MIN_CONNS", 2))
+	config.HealthCheckPeriod = envDuration("DB_HEALTH_CHECK_PERIOD", 30*time.Second)
+	config.MaxConnLifetime = envDuration("DB_MAX_CONN_LIFETIME", time.Hour)
+	config.MaxConnIdleTime = envDuration("DB_MAX_CONN_IDLE_TIME", 10*time.Minute)
+
+	statementTimeout := envDuration("DB_STATEMENT_TIMEOUT", 15*time.Second)
+	config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(statementTimeout.Milliseconds(), 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	DB, err = pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		log.Fatal("Database connection error:", err)
 	}
 
-	var version string
-	if err := DB.QueryRow(context.Background(), "SELECT version()").Scan(&version); err != nil {
-		log.Fatal("Database query error:", err)
+	if err := DB.Ping(ctx); err != nil {
+		log.Fatal("Database ping error:", err)
 	}
 
-	createTables()
-	
 	return DB
 }
 
-func createTables() {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS products (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			description TEXT,
-			price DECIMAL(10,2),
-			seller_id INTEGER NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS images (
-			id SERIAL PRIMARY KEY,
-			product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
-			image_url TEXT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS pinned_products (
-			id SERIAL PRIMARY KEY,
-			product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
-			seller_id INTEGER NOT NULL,
-			similarity_score DECIMAL(3,2),
-			is_pinned BOOLEAN DEFAULT false,
-			pinned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE(product_id, seller_id)
-		)`,
-		`CREATE TABLE IF NOT EXISTS livestreams (
-			id SERIAL PRIMARY KEY,
-			seller_id VARCHAR(255) NOT NULL,
-			seller_name VARCHAR(255) NOT NULL,
-			title VARCHAR(255) NOT NULL,
-			description TEXT,
-			is_live BOOLEAN DEFAULT true,
-			viewer_count INTEGER DEFAULT 0,
-			started_at TIMESTAMP NOT NULL,
-			ended_at TIMESTAMP,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_livestreams_seller_id ON livestreams(seller_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_livestreams_is_live ON livestreams(is_live)`,
-	}
+// Ready reports whether the pool can currently serve queries.
+func Ready(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return DB.Ping(ctx)
+}
 
-	for _, query := range queries {
-		if _, err := DB.Exec(context.Background(), query); err != nil {
-		}
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
 	}
+	return fallback
+}
 
-}
\ No newline at end of file
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
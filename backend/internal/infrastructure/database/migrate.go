@@ -0,0 +1,268 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the pg_advisory_lock key held while migrating, so only
+// one backend replica applies migrations at a time.
+const migrationLockKey int64 = 7283640195
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// migrationConn is satisfied by both the pool and a single acquired
+// connection, so the same helpers serve locked and unlocked paths.
+type migrationConn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn migrationConn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration, migration.Up, true); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the given number of most recently applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+
+	err := m.withLock(ctx, func(conn migrationConn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back: no down file", migration.Version, migration.Name)
+			}
+			if err := m.apply(ctx, conn, migration, migration.Down, false); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status lists every known migration with the time it was applied, if any.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+
+	done, err := m.appliedVersions(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := done[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn migrationConn, migration Migration, sql string, up bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		return fmt.Errorf("migration %d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+	} else {
+		_, err = tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// withLock runs fn on a single pooled connection holding the migration
+// advisory lock, with no statement timeout. The lock is session scoped, so every statement issued while
+// it is held must go through that same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn migrationConn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// Waiting for another replica's migrations and long DDL must not be cut
+	// short by the pool's DB_STATEMENT_TIMEOUT. RESET restores the pool's
+	// value before the connection goes back to it.
+	if _, err := conn.Exec(ctx, `SET statement_timeout = 0`); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), `RESET statement_timeout`)
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if err := m.ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) ensureMigrationsTable(ctx context.Context, conn migrationConn) error {
+	_, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn migrationConn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
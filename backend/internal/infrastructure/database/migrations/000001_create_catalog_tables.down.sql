@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS livestreams;
+DROP TABLE IF EXISTS pinned_products;
+DROP TABLE IF EXISTS images;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(10,2),
+    seller_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    image_url TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS pinned_products (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    seller_id INTEGER NOT NULL,
+    similarity_score DECIMAL(3,2),
+    is_pinned BOOLEAN DEFAULT false,
+    pinned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(product_id, seller_id)
+);
+
+CREATE TABLE IF NOT EXISTS livestreams (
+    id SERIAL PRIMARY KEY,
+    seller_id VARCHAR(255) NOT NULL,
+    seller_name VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    is_live BOOLEAN DEFAULT true,
+    viewer_count INTEGER DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_livestreams_seller_id ON livestreams(seller_id);
+CREATE INDEX IF NOT EXISTS idx_livestreams_is_live ON livestreams(is_live);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_products_seller_id;
+
+ALTER TABLE livestreams DROP CONSTRAINT IF EXISTS fk_livestreams_seller;
+ALTER TABLE pinned_products DROP CONSTRAINT IF EXISTS fk_pinned_products_seller;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_seller;
+
+ALTER TABLE livestreams ALTER COLUMN seller_id TYPE VARCHAR(255) USING seller_id::VARCHAR;
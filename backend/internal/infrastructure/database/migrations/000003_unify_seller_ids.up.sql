@@ -0,0 +1,55 @@
+-- Convert the legacy free-form livestream seller IDs to integers, create a
+-- seller row for every ID already in use so existing data stays attached, then
+-- tie products, pins and livestreams to sellers with foreign keys.
+--
+-- Only IDs that are already plain positive integers are converted. Anything else stops
+-- the migration so the rows can be fixed by hand rather than being deleted or
+-- merged with another seller's.
+DO $$
+DECLARE
+    bad_ids TEXT;
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'livestreams' AND column_name = 'seller_id' AND data_type <> 'integer'
+    ) THEN
+        SELECT string_agg(DISTINCT quote_literal(seller_id), ', ') INTO bad_ids
+        FROM livestreams
+        WHERE CASE WHEN seller_id ~ '^[1-9][0-9]*$' THEN seller_id::NUMERIC > 2147483647 ELSE TRUE END;
+
+        IF bad_ids IS NOT NULL THEN
+            RAISE EXCEPTION 'livestreams.seller_id values are not integer seller IDs: %', bad_ids
+                USING HINT = 'Update these livestreams to the numeric ID of their seller, then run the migration again.';
+        END IF;
+
+        ALTER TABLE livestreams ALTER COLUMN seller_id TYPE INTEGER USING seller_id::INTEGER;
+    END IF;
+END $$;
+
+-- Backfilled sellers have no user, so nobody can sign in as them until an
+-- operator claims each one for its owner's account once they have registered:
+--   UPDATE sellers SET user_id = <users.id> WHERE id = <seller id> AND user_id IS NULL;
+INSERT INTO sellers (id, shop_name)
+SELECT ids.seller_id, 'Seller ' || ids.seller_id
+FROM (
+    SELECT seller_id FROM products
+    UNION SELECT seller_id FROM pinned_products
+    UNION SELECT seller_id FROM livestreams
+) ids
+WHERE NOT EXISTS (SELECT 1 FROM sellers s WHERE s.id = ids.seller_id);
+
+SELECT setval(pg_get_serial_sequence('sellers', 'id'), GREATEST((SELECT MAX(id) FROM sellers), 1));
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_seller;
+ALTER TABLE products ADD CONSTRAINT fk_products_seller
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE;
+
+ALTER TABLE pinned_products DROP CONSTRAINT IF EXISTS fk_pinned_products_seller;
+ALTER TABLE pinned_products ADD CONSTRAINT fk_pinned_products_seller
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE;
+
+ALTER TABLE livestreams DROP CONSTRAINT IF EXISTS fk_livestreams_seller;
+ALTER TABLE livestreams ADD CONSTRAINT fk_livestreams_seller
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_products_seller_id ON products(seller_id);
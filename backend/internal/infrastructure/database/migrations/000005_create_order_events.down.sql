@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_seller_status;
+DROP TABLE IF EXISTS order_events;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
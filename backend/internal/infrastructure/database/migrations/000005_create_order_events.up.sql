@@ -0,0 +1,18 @@
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'packed', 'shipped', 'delivered', 'cancelled', 'refunded'));
+
+CREATE TABLE IF NOT EXISTS order_events (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_order_id ON order_events(order_id);
+CREATE INDEX IF NOT EXISTS idx_orders_seller_status ON orders(seller_id, status);
+
+INSERT INTO order_events (order_id, from_status, to_status, created_at)
+SELECT id, NULL, status, created_at FROM orders;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_payment_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    client_secret VARCHAR(255),
+    checkout_url TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(provider, provider_payment_id)
+);
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    payment_id INTEGER REFERENCES payments(id) ON DELETE SET NULL,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(provider, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments(order_id);
@@ -0,0 +1,22 @@
+-- Products listed before stock was tracked start with a generous stock so
+-- they stay buyable, including from carts already in progress, until their
+-- seller sets the real figure. Products created from now on start at 0.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock INTEGER NOT NULL DEFAULT 1000;
+ALTER TABLE products ALTER COLUMN stock SET DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT chk_products_stock CHECK (stock >= 0);
+
+-- A reservation holds stock for one cart item until it expires. Available
+-- stock is products.stock minus unexpired reservations, so an abandoned
+-- reservation stops counting the moment it expires even before the sweeper
+-- deletes it.
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    cart_item_id INTEGER NOT NULL UNIQUE REFERENCES cart_items(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product_id ON stock_reservations(product_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expires_at ON stock_reservations(expires_at);
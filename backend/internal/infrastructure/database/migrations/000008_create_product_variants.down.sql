@@ -0,0 +1,17 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_name;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+DROP INDEX IF EXISTS idx_stock_reservations_variant_id;
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS variant_id;
+
+DROP INDEX IF EXISTS idx_cart_items_unique_line;
+DELETE FROM cart_items WHERE variant_id IS NOT NULL;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_cart_id_product_id_livestream_id_key UNIQUE (cart_id, product_id, livestream_id);
+
+ALTER TABLE pinned_products DROP COLUMN IF EXISTS variant_id;
+DELETE FROM images WHERE variant_id IS NOT NULL;
+ALTER TABLE images DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,40 @@
+-- Option axes a product varies along, e.g. size: [S, M, L].
+CREATE TABLE IF NOT EXISTS product_options (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    option_values TEXT[] NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(product_id, name)
+);
+
+-- One purchasable combination of option values, e.g. {"size": "M", "colour": "red"}.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(100),
+    options JSONB NOT NULL DEFAULT '{}',
+    price DECIMAL(10,2) NOT NULL,
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(product_id, options)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants(sku) WHERE sku IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_product_options_product_id ON product_options(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id);
+
+ALTER TABLE images ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE pinned_products ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_cart_id_product_id_livestream_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_unique_line
+    ON cart_items(cart_id, product_id, livestream_id, (COALESCE(variant_id, 0)));
+
+ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_variant_id ON stock_reservations(variant_id, expires_at);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_livestreams_started_at_id;
+DROP INDEX IF EXISTS idx_images_product_id;
+DROP INDEX IF EXISTS idx_products_seller_created_at_id;
+DROP INDEX IF EXISTS idx_products_price_id;
+DROP INDEX IF EXISTS idx_products_created_at_id;
@@ -0,0 +1,6 @@
+-- Keyset pagination orders listings by a sort column with id as tie-breaker.
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products((COALESCE(price, 0)), id);
+CREATE INDEX IF NOT EXISTS idx_products_seller_created_at_id ON products(seller_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_images_product_id ON images(product_id);
+CREATE INDEX IF NOT EXISTS idx_livestreams_started_at_id ON livestreams(started_at, id);
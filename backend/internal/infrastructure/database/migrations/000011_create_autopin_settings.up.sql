@@ -0,0 +1,12 @@
+-- Per-seller policy for pinning products detected in the live stream. In
+-- suggest mode the seller is asked to confirm instead of the product being
+-- pinned outright.
+CREATE TABLE IF NOT EXISTS autopin_settings (
+    seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+    mode VARCHAR(16) NOT NULL DEFAULT 'auto' CHECK (mode IN ('off', 'suggest', 'auto')),
+    min_similarity DOUBLE PRECISION NOT NULL DEFAULT 0.8 CHECK (min_similarity BETWEEN 0 AND 1),
+    min_consecutive_frames INTEGER NOT NULL DEFAULT 3 CHECK (min_consecutive_frames > 0),
+    cooldown_seconds INTEGER NOT NULL DEFAULT 30 CHECK (cooldown_seconds >= 0),
+    max_pinned INTEGER NOT NULL DEFAULT 1 CHECK (max_pinned > 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,17 @@
+-- One row per product recognised in a sampled frame of a live stream. Kept
+-- narrow because a busy stream writes a few rows every couple of seconds.
+CREATE TABLE IF NOT EXISTS stream_detections (
+    id BIGSERIAL PRIMARY KEY,
+    livestream_id INTEGER NOT NULL REFERENCES livestreams(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    detected_at TIMESTAMP NOT NULL,
+    x1 SMALLINT NOT NULL,
+    y1 SMALLINT NOT NULL,
+    x2 SMALLINT NOT NULL,
+    y2 SMALLINT NOT NULL,
+    similarity REAL NOT NULL,
+    confidence REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stream_detections_timeline
+    ON stream_detections(livestream_id, product_id, detected_at);
@@ -0,0 +1,23 @@
+-- Training runs on the ML service, tracked here so their progress and
+-- outcome survive restarts of either service.
+CREATE TABLE IF NOT EXISTS training_jobs (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    fine_tune BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    message TEXT NOT NULL DEFAULT '',
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The ML service keeps one training status per seller, so a seller may only
+-- have one job in flight.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_training_jobs_active_seller
+    ON training_jobs(seller_id) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_training_jobs_seller_created
+    ON training_jobs(seller_id, created_at DESC, id DESC);
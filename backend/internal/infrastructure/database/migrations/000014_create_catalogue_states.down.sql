@@ -0,0 +1 @@
+DROP TABLE IF EXISTS catalogue_states;
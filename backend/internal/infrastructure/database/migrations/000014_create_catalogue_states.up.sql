@@ -0,0 +1,12 @@
+-- Whether each seller's ML index is behind their catalogue. changed_at is
+-- the latest unindexed change and drives the debounced retrain.
+CREATE TABLE IF NOT EXISTS catalogue_states (
+    seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+    auto_retrain BOOLEAN NOT NULL DEFAULT true,
+    dirty_since TIMESTAMP,
+    changed_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalogue_states_changed
+    ON catalogue_states(changed_at) WHERE changed_at IS NOT NULL AND auto_retrain;
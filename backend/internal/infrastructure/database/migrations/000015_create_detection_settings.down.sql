@@ -0,0 +1 @@
+DROP TABLE IF EXISTS detection_settings;
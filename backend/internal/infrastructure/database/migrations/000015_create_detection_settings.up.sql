@@ -0,0 +1,19 @@
+-- Per-seller tuning of product detection, sent to the ML service with every
+-- prediction. The region of interest is in fractions of the frame's size and
+-- is either set in full or not at all.
+CREATE TABLE IF NOT EXISTS detection_settings (
+    seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+    similarity_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.7 CHECK (similarity_threshold BETWEEN 0 AND 1),
+    min_confidence DOUBLE PRECISION NOT NULL DEFAULT 0.4 CHECK (min_confidence BETWEEN 0 AND 1),
+    ignored_classes TEXT[] NOT NULL DEFAULT ARRAY['person'],
+    roi_x DOUBLE PRECISION,
+    roi_y DOUBLE PRECISION,
+    roi_width DOUBLE PRECISION,
+    roi_height DOUBLE PRECISION,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        (roi_x IS NULL AND roi_y IS NULL AND roi_width IS NULL AND roi_height IS NULL)
+        OR (roi_x >= 0 AND roi_y >= 0 AND roi_width > 0 AND roi_height > 0
+            AND roi_x + roi_width <= 1 AND roi_y + roi_height <= 1)
+    )
+);
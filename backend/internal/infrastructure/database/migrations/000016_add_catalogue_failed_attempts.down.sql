@@ -0,0 +1 @@
+ALTER TABLE catalogue_states DROP COLUMN IF EXISTS failed_attempts;
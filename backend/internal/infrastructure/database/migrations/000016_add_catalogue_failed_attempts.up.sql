@@ -0,0 +1,4 @@
+-- Consecutive failed trainings since the catalogue last changed. Each one
+-- doubles how long the scheduler waits before trying again, so a catalogue
+-- that cannot be trained is not retried every quiet period forever.
+ALTER TABLE catalogue_states ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
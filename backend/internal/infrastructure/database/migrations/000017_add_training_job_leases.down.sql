@@ -0,0 +1,2 @@
+ALTER TABLE training_jobs DROP COLUMN IF EXISTS lease_until;
+ALTER TABLE training_jobs DROP COLUMN IF EXISTS runner_id;
@@ -0,0 +1,5 @@
+-- Every replica resumes unfinished jobs at startup, so a job is leased to the
+-- replica running it. The runner keeps renewing the lease; a job whose lease
+-- has run out was left behind and may be claimed by another replica.
+ALTER TABLE training_jobs ADD COLUMN IF NOT EXISTS runner_id VARCHAR(255);
+ALTER TABLE training_jobs ADD COLUMN IF NOT EXISTS lease_until TIMESTAMP;
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"strings"
+)
+
+// sortKey is one way a listing can be ordered. Rows are ordered by expr and
+// then by the table's id in the same direction, so every row has a unique
+// position a cursor can point at; cast converts the cursor's text value
+// back to expr's type.
+type sortKey struct {
+	expr string
+	cast string
+	desc bool
+}
+
+// conditions collects WHERE clauses and their arguments, numbering the
+// placeholders as they are added.
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add appends a clause written with ? placeholders, one per argument.
+func (c *conditions) add(clause string, args ...interface{}) {
+	for _, arg := range args {
+		c.args = append(c.args, arg)
+		clause = strings.Replace(clause, "?", fmt.Sprintf("$%d", len(c.args)), 1)
+	}
+	c.clauses = append(c.clauses, clause)
+}
+
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// after restricts the rows to those past the cursor.
+func (k sortKey) after(c *conditions, idExpr string, cursor *entities.PageCursor) {
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	c.add(fmt.Sprintf("(%s, %s) %s (?::%s, ?)", k.expr, idExpr, op, k.cast), cursor.Value, cursor.ID)
+}
+
+func (k sortKey) orderBy(idExpr string) string {
+	direction := "ASC"
+	if k.desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", k.expr, direction, idExpr, direction)
+}
+
+// timestampCursorLayout formats TIMESTAMP values for cursors so that casting
+// them back with ::timestamp yields the same instant.
+const timestampCursorLayout = "2006-01-02T15:04:05.999999"
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresAutoPinRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAutoPinRepository(db *pgxpool.Pool) repositories.AutoPinRepository {
+	return &postgresAutoPinRepository{db: db}
+}
+
+func (r *postgresAutoPinRepository) FindSettings(ctx context.Context, sellerID int) (*entities.AutoPinSettings, error) {
+	query := `
+		SELECT seller_id, mode, min_similarity, min_consecutive_frames, cooldown_seconds, max_pinned, updated_at
+		FROM autopin_settings
+		WHERE seller_id = $1
+	`
+
+	var settings entities.AutoPinSettings
+	err := r.db.QueryRow(ctx, query, sellerID).Scan(
+		&settings.SellerID, &settings.Mode, &settings.MinSimilarity, &settings.MinConsecutiveFrames,
+		&settings.CooldownSeconds, &settings.MaxPinned, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &settings, nil
+}
+
+func (r *postgresAutoPinRepository) SaveSettings(ctx context.Context, settings *entities.AutoPinSettings) error {
+	query := `
+		INSERT INTO autopin_settings (seller_id, mode, min_similarity, min_consecutive_frames, cooldown_seconds, max_pinned, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		ON CONFLICT (seller_id) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			min_similarity = EXCLUDED.min_similarity,
+			min_consecutive_frames = EXCLUDED.min_consecutive_frames,
+			cooldown_seconds = EXCLUDED.cooldown_seconds,
+			max_pinned = EXCLUDED.max_pinned,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+	return mapPgError(r.db.QueryRow(ctx, query,
+		settings.SellerID, settings.Mode, settings.MinSimilarity, settings.MinConsecutiveFrames,
+		settings.CooldownSeconds, settings.MaxPinned,
+	).Scan(&settings.UpdatedAt))
+}
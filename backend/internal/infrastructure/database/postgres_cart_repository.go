@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresCartRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCartRepository(db *pgxpool.Pool) repositories.CartRepository {
+	return &postgresCartRepository{db: db}
+}
+
+func (r *postgresCartRepository) FindOrCreateByUserID(ctx context.Context, userID int) (*entities.Cart, error) {
+	// The no-op update makes RETURNING yield the existing row on conflict.
+	cartQuery := `
+		INSERT INTO carts (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING id, user_id, created_at, updated_at
+	`
+
+	var cart entities.Cart
+	err := r.db.QueryRow(ctx, cartQuery, userID).Scan(&cart.ID, &cart.UserID, &cart.CreatedAt, &cart.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsQuery := `
+		SELECT ci.id, ci.cart_id, ci.product_id, ci.variant_id, ci.livestream_id, ci.quantity, sr.expires_at, ci.added_at, ci.updated_at,
+		       p.name, p.description, p.price, p.seller_id, p.stock,
+		       COALESCE(v.sku, ''), v.options, v.price, v.stock
+		FROM cart_items ci
+		JOIN products p ON ci.product_id = p.id
+		LEFT JOIN product_variants v ON ci.variant_id = v.id
+		LEFT JOIN stock_reservations sr ON sr.cart_item_id = ci.id AND sr.expires_at > CURRENT_TIMESTAMP
+		WHERE ci.cart_id = $1
+		ORDER BY ci.added_at
+	`
+
+	rows, err := r.db.Query(ctx, itemsQuery, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cart.Items = []entities.CartItem{}
+	for rows.Next() {
+		var item entities.CartItem
+		var p entities.Product
+		var v entities.ProductVariant
+		var variantPrice *float64
+		var variantStock *int
+		err := rows.Scan(
+			&item.ID, &item.CartID, &item.ProductID, &item.VariantID, &item.LiveStreamID, &item.Quantity, &item.ReservedUntil, &item.AddedAt, &item.UpdatedAt,
+			&p.Name, &p.Description, &p.Price, &p.SellerID, &p.Stock,
+			&v.SKU, &v.Options, &variantPrice, &variantStock,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		p.ID = item.ProductID
+		item.Product = &p
+		if item.VariantID != nil && variantPrice != nil {
+			v.ID = *item.VariantID
+			v.ProductID = item.ProductID
+			v.Price = *variantPrice
+			v.Stock = *variantStock
+			item.Variant = &v
+		}
+		cart.Items = append(cart.Items, item)
+		cart.Total += item.UnitPrice() * float64(item.Quantity)
+	}
+
+	return &cart, rows.Err()
+}
+
+func (r *postgresCartRepository) AddItem(ctx context.Context, item *entities.CartItem, ttl time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO cart_items (cart_id, product_id, variant_id, livestream_id, quantity)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cart_id, product_id, livestream_id, (COALESCE(variant_id, 0)))
+		DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, quantity, added_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, item.CartID, item.ProductID, item.VariantID, item.LiveStreamID, item.Quantity).
+		Scan(&item.ID, &item.Quantity, &item.AddedAt, &item.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := reserveStock(ctx, tx, item.ID, newStockKey(item.ProductID, item.VariantID), item.Quantity, ttl); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresCartRepository) UpdateItemQuantity(ctx context.Context, cartID int, itemID int, quantity int, ttl time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE cart_items SET quantity = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND cart_id = $3 RETURNING product_id, variant_id`
+	var productID int
+	var variantID *int
+	if err := tx.QueryRow(ctx, query, quantity, itemID, cartID).Scan(&productID, &variantID); err != nil {
+		return mapPgError(err)
+	}
+
+	if err := reserveStock(ctx, tx, itemID, newStockKey(productID, variantID), quantity, ttl); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresCartRepository) RemoveItem(ctx context.Context, cartID int, itemID int) error {
+	query := `DELETE FROM cart_items WHERE id = $1 AND cart_id = $2`
+	result, err := r.db.Exec(ctx, query, itemID, cartID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresCartRepository) Checkout(ctx context.Context, cartID int, itemIDs []int, orders []entities.Order) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Deleting first locks the items, so a concurrent checkout of the same
+	// cart sees fewer rows and fails instead of creating duplicate orders.
+	result, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE cart_id = $1 AND id = ANY($2)`, cartID, itemIDs)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() != int64(len(itemIDs)) {
+		return entities.ErrConflict
+	}
+
+	// Deduct in product and variant ID order so concurrent checkouts lock
+	// stock rows in the same order and cannot deadlock.
+	quantities := make(map[stockKey]int)
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if item.ProductID != nil {
+				quantities[newStockKey(*item.ProductID, item.VariantID)] += item.Quantity
+			}
+		}
+	}
+	keys := make([]stockKey, 0, len(quantities))
+	for key := range quantities {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ProductID != keys[j].ProductID {
+			return keys[i].ProductID < keys[j].ProductID
+		}
+		return keys[i].VariantID < keys[j].VariantID
+	})
+	for _, key := range keys {
+		if err := deductStock(ctx, tx, key, quantities[key]); err != nil {
+			return err
+		}
+	}
+
+	for i := range orders {
+		if err := insertOrder(ctx, tx, &orders[i]); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE carts SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, cartID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
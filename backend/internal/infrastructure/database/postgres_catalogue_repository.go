@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxRetrainBackoffDoublings caps how far failed trainings stretch the quiet
+// period: at most 64 times, about five hours at the default.
+const maxRetrainBackoffDoublings = 6
+
+type postgresCatalogueRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCatalogueRepository(db *pgxpool.Pool) repositories.CatalogueRepository {
+	return &postgresCatalogueRepository{db: db}
+}
+
+func (r *postgresCatalogueRepository) FindState(ctx context.Context, sellerID int) (*entities.CatalogueState, error) {
+	query := `
+		SELECT seller_id, auto_retrain, dirty_since, changed_at, failed_attempts, updated_at
+		FROM catalogue_states
+		WHERE seller_id = $1
+	`
+
+	var state entities.CatalogueState
+	err := r.db.QueryRow(ctx, query, sellerID).Scan(
+		&state.SellerID, &state.AutoRetrain, &state.DirtySince, &state.ChangedAt, &state.FailedAttempts, &state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &state, nil
+}
+
+func (r *postgresCatalogueRepository) SaveAutoRetrain(ctx context.Context, sellerID int, autoRetrain bool) (*entities.CatalogueState, error) {
+	query := `
+		INSERT INTO catalogue_states (seller_id, auto_retrain, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (seller_id) DO UPDATE SET
+			auto_retrain = EXCLUDED.auto_retrain,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING seller_id, auto_retrain, dirty_since, changed_at, failed_attempts, updated_at
+	`
+
+	var state entities.CatalogueState
+	err := r.db.QueryRow(ctx, query, sellerID, autoRetrain).Scan(
+		&state.SellerID, &state.AutoRetrain, &state.DirtySince, &state.ChangedAt, &state.FailedAttempts, &state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &state, nil
+}
+
+func (r *postgresCatalogueRepository) MarkDirty(ctx context.Context, sellerID int) error {
+	query := `
+		INSERT INTO catalogue_states (seller_id, dirty_since, changed_at, updated_at)
+		VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (seller_id) DO UPDATE SET
+			dirty_since = COALESCE(catalogue_states.dirty_since, CURRENT_TIMESTAMP),
+			changed_at = CURRENT_TIMESTAMP,
+			failed_attempts = 0,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.Exec(ctx, query, sellerID)
+	return mapPgError(err)
+}
+
+func (r *postgresCatalogueRepository) MarkFailed(ctx context.Context, sellerID int) error {
+	query := `
+		INSERT INTO catalogue_states (seller_id, dirty_since, changed_at, failed_attempts, updated_at)
+		VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (seller_id) DO UPDATE SET
+			dirty_since = COALESCE(catalogue_states.dirty_since, CURRENT_TIMESTAMP),
+			changed_at = COALESCE(catalogue_states.changed_at, CURRENT_TIMESTAMP),
+			failed_attempts = catalogue_states.failed_attempts + 1,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.Exec(ctx, query, sellerID)
+	return mapPgError(err)
+}
+
+func (r *postgresCatalogueRepository) MarkClean(ctx context.Context, sellerID int) error {
+	query := `
+		UPDATE catalogue_states
+		SET dirty_since = NULL, changed_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE seller_id = $1 AND dirty_since IS NOT NULL
+	`
+	_, err := r.db.Exec(ctx, query, sellerID)
+	return err
+}
+
+func (r *postgresCatalogueRepository) FindDue(ctx context.Context, quiet time.Duration) ([]int, error) {
+	query := `
+		SELECT seller_id
+		FROM catalogue_states
+		WHERE auto_retrain AND changed_at IS NOT NULL
+		  AND changed_at <= CURRENT_TIMESTAMP
+		      - make_interval(secs => $1 * power(2, LEAST(failed_attempts, $2)))
+		ORDER BY changed_at
+	`
+
+	rows, err := r.db.Query(ctx, query, quiet.Seconds(), maxRetrainBackoffDoublings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sellerIDs := []int{}
+	for rows.Next() {
+		var sellerID int
+		if err := rows.Scan(&sellerID); err != nil {
+			return nil, err
+		}
+		sellerIDs = append(sellerIDs, sellerID)
+	}
+	return sellerIDs, rows.Err()
+}
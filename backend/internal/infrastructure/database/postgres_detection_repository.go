@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresDetectionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDetectionRepository(db *pgxpool.Pool) repositories.DetectionRepository {
+	return &postgresDetectionRepository{db: db}
+}
+
+func (r *postgresDetectionRepository) Record(ctx context.Context, detections []entities.Detection) error {
+	if len(detections) == 0 {
+		return nil
+	}
+
+	// One statement per frame: the columns are sent as parallel arrays and
+	// unnested back into rows.
+	var (
+		liveStreamIDs, productIDs []int32
+		x1, y1, x2, y2            []int32
+		similarities, confidences []float64
+		detectedAts               []string
+	)
+	for _, d := range detections {
+		liveStreamIDs = append(liveStreamIDs, int32(d.LiveStreamID))
+		productIDs = append(productIDs, int32(d.ProductID))
+		x1 = append(x1, int32(d.BBox[0]))
+		y1 = append(y1, int32(d.BBox[1]))
+		x2 = append(x2, int32(d.BBox[2]))
+		y2 = append(y2, int32(d.BBox[3]))
+		similarities = append(similarities, d.Similarity)
+		confidences = append(confidences, d.Confidence)
+		detectedAts = append(detectedAts, d.DetectedAt.UTC().Format(timestampCursorLayout))
+	}
+
+	query := `
+		INSERT INTO stream_detections (livestream_id, product_id, detected_at, x1, y1, x2, y2, similarity, confidence)
+		SELECT d.livestream_id, d.product_id, d.detected_at, d.x1, d.y1, d.x2, d.y2, d.similarity, d.confidence
+		FROM unnest($1::int[], $2::int[], $3::timestamp[], $4::smallint[], $5::smallint[], $6::smallint[], $7::smallint[], $8::real[], $9::real[])
+		     AS d(livestream_id, product_id, detected_at, x1, y1, x2, y2, similarity, confidence)
+		WHERE EXISTS (SELECT 1 FROM products p WHERE p.id = d.product_id)
+	`
+	_, err := r.db.Exec(ctx, query, liveStreamIDs, productIDs, detectedAts, x1, y1, x2, y2, similarities, confidences)
+	return err
+}
+
+func (r *postgresDetectionRepository) FindAppearances(ctx context.Context, liveStreamID int, gapSeconds int, productID *int) ([]entities.ProductAppearance, error) {
+	// A detection starts a new appearance when the previous detection of the
+	// same product is more than the gap before it; numbering the starts
+	// with a running sum labels each appearance.
+	query := `
+		SELECT product_id, MIN(detected_at), MAX(detected_at), COUNT(*), MAX(similarity)
+		FROM (
+			SELECT product_id, detected_at, similarity,
+			       SUM(starts_appearance) OVER (PARTITION BY product_id ORDER BY detected_at, id) AS appearance
+			FROM (
+				SELECT id, product_id, detected_at, similarity,
+				       CASE WHEN detected_at - LAG(detected_at) OVER (PARTITION BY product_id ORDER BY detected_at, id)
+				                 <= make_interval(secs => $2)
+				            THEN 0 ELSE 1 END AS starts_appearance
+				FROM stream_detections
+				WHERE livestream_id = $1 AND ($3::int IS NULL OR product_id = $3)
+			) marked
+		) numbered
+		GROUP BY product_id, appearance
+		ORDER BY product_id, MIN(detected_at)
+	`
+
+	rows, err := r.db.Query(ctx, query, liveStreamID, gapSeconds, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appearances := []entities.ProductAppearance{}
+	for rows.Next() {
+		var a entities.ProductAppearance
+		var maxSimilarity float32
+		if err := rows.Scan(&a.ProductID, &a.StartedAt, &a.EndedAt, &a.Detections, &maxSimilarity); err != nil {
+			return nil, err
+		}
+		a.MaxSimilarity = float64(maxSimilarity)
+		appearances = append(appearances, a)
+	}
+	return appearances, rows.Err()
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresDetectionSettingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDetectionSettingsRepository(db *pgxpool.Pool) repositories.DetectionSettingsRepository {
+	return &postgresDetectionSettingsRepository{db: db}
+}
+
+func (r *postgresDetectionSettingsRepository) FindSettings(ctx context.Context, sellerID int) (*entities.DetectionSettings, error) {
+	query := `
+		SELECT seller_id, similarity_threshold, min_confidence, ignored_classes,
+		       roi_x, roi_y, roi_width, roi_height, updated_at
+		FROM detection_settings
+		WHERE seller_id = $1
+	`
+
+	var (
+		settings               entities.DetectionSettings
+		roiX, roiY, roiW, roiH *float64
+	)
+	err := r.db.QueryRow(ctx, query, sellerID).Scan(
+		&settings.SellerID, &settings.SimilarityThreshold, &settings.MinConfidence, &settings.IgnoredClasses,
+		&roiX, &roiY, &roiW, &roiH, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	if roiX != nil && roiY != nil && roiW != nil && roiH != nil {
+		settings.ROI = &entities.RegionOfInterest{X: *roiX, Y: *roiY, Width: *roiW, Height: *roiH}
+	}
+	return &settings, nil
+}
+
+func (r *postgresDetectionSettingsRepository) SaveSettings(ctx context.Context, settings *entities.DetectionSettings) error {
+	query := `
+		INSERT INTO detection_settings (seller_id, similarity_threshold, min_confidence, ignored_classes,
+		                                roi_x, roi_y, roi_width, roi_height, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+		ON CONFLICT (seller_id) DO UPDATE SET
+			similarity_threshold = EXCLUDED.similarity_threshold,
+			min_confidence = EXCLUDED.min_confidence,
+			ignored_classes = EXCLUDED.ignored_classes,
+			roi_x = EXCLUDED.roi_x,
+			roi_y = EXCLUDED.roi_y,
+			roi_width = EXCLUDED.roi_width,
+			roi_height = EXCLUDED.roi_height,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	var roiX, roiY, roiW, roiH *float64
+	if roi := settings.ROI; roi != nil {
+		roiX, roiY, roiW, roiH = &roi.X, &roi.Y, &roi.Width, &roi.Height
+	}
+	ignoredClasses := settings.IgnoredClasses
+	if ignoredClasses == nil {
+		ignoredClasses = []string{}
+	}
+
+	return mapPgError(r.db.QueryRow(ctx, query,
+		settings.SellerID, settings.SimilarityThreshold, settings.MinConfidence, ignoredClasses,
+		roiX, roiY, roiW, roiH,
+	).Scan(&settings.UpdatedAt))
+}
+
+func (r *postgresDetectionSettingsRepository) DeleteSettings(ctx context.Context, sellerID int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM detection_settings WHERE seller_id = $1`, sellerID)
+	return mapPgError(err)
+}
@@ -2,17 +2,19 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"live-shopping-ai/backend/internal/domain/entities"
+	"strconv"
 	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PostgresLiveStreamRepository struct{
-		db *pgx.Conn
+		db *pgxpool.Pool
 }
 
-func NewPostgresLiveStreamRepository(db *pgx.Conn) *PostgresLiveStreamRepository {
+func NewPostgresLiveStreamRepository(db *pgxpool.Pool) *PostgresLiveStreamRepository {
 	return &PostgresLiveStreamRepository{
 		db: db,
 	}
@@ -24,7 +26,9 @@ func (r *PostgresLiveStreamRepository) CreateLiveStream(stream *entities.LiveStr
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 	
-	now := time.Now()
+	// TIMESTAMP columns hold UTC wall-clock time, matching CURRENT_TIMESTAMP
+	// in a UTC session and the timestamps compared against them.
+	now := time.Now().UTC()
 	return r.db.QueryRow(context.Background(), query,
 		stream.SellerID,
 		stream.SellerName,
@@ -32,13 +36,40 @@ func (r *PostgresLiveStreamRepository) CreateLiveStream(stream *entities.LiveStr
 		stream.Description,
 		stream.IsLive,
 		stream.ViewerCount,
-		stream.StartedAt,
+		stream.StartedAt.UTC(),
 		now,
 		now,
 	).Scan(&stream.ID)
 }
 
-func (r *PostgresLiveStreamRepository) GetLiveStreamBySellerID(sellerID string) (*entities.LiveStream, error) {
+func (r *PostgresLiveStreamRepository) GetLiveStreamByID(id int) (*entities.LiveStream, error) {
+	query := `
+		SELECT id, seller_id, seller_name, title, description, is_live, viewer_count, started_at, ended_at, created_at, updated_at
+		FROM livestreams 
+		WHERE id = $1`
+	
+	stream := &entities.LiveStream{}
+	err := r.db.QueryRow(context.Background(), query, id).Scan(
+		&stream.ID,
+		&stream.SellerID,
+		&stream.SellerName,
+		&stream.Title,
+		&stream.Description,
+		&stream.IsLive,
+		&stream.ViewerCount,
+		&stream.StartedAt,
+		&stream.EndedAt,
+		&stream.CreatedAt,
+		&stream.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	
+	return stream, nil
+}
+
+func (r *PostgresLiveStreamRepository) GetLiveStreamBySellerID(sellerID int) (*entities.LiveStream, error) {
 	query := `
 		SELECT id, seller_id, seller_name, title, description, is_live, viewer_count, started_at, ended_at, created_at, updated_at
 		FROM livestreams 
@@ -68,20 +99,63 @@ func (r *PostgresLiveStreamRepository) GetLiveStreamBySellerID(sellerID string)
 	return stream, nil
 }
 
-func (r *PostgresLiveStreamRepository) GetActiveLiveStreams() ([]entities.LiveStream, error) {
-	query := `
+var liveStreamSortKeys = map[string]sortKey{
+	entities.LiveStreamSortNewest:  {expr: "started_at", cast: "timestamp", desc: true},
+	entities.LiveStreamSortOldest:  {expr: "started_at", cast: "timestamp"},
+	entities.LiveStreamSortViewers: {expr: "viewer_count", cast: "integer", desc: true},
+}
+
+func (r *PostgresLiveStreamRepository) ListLiveStreams(filter *entities.LiveStreamFilter) (*entities.LiveStreamPage, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = entities.LiveStreamSortNewest
+	}
+	key, ok := liveStreamSortKeys[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort %q", entities.ErrInvalidInput, sort)
+	}
+
+	var conds conditions
+	if filter.SellerID != nil {
+		conds.add("seller_id = ?", *filter.SellerID)
+	}
+	if filter.IsLive != nil {
+		conds.add("is_live = ?", *filter.IsLive)
+	}
+	if filter.StartedAfter != nil {
+		conds.add("started_at > ?::timestamp", filter.StartedAfter.UTC().Format(timestampCursorLayout))
+	}
+
+	ctx := context.Background()
+	page := &entities.LiveStreamPage{Data: []entities.LiveStream{}}
+	countQuery := `SELECT COUNT(*) FROM livestreams ` + conds.where()
+	if err := r.db.QueryRow(ctx, countQuery, conds.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := entities.DecodePageCursor(filter.Cursor, sort)
+		if err != nil {
+			return nil, err
+		}
+		key.after(&conds, "id", cursor)
+	}
+
+	// One extra row tells whether there is a next page.
+	limit := entities.PageLimit(filter.Limit)
+	query := fmt.Sprintf(`
 		SELECT id, seller_id, seller_name, title, description, is_live, viewer_count, started_at, ended_at, created_at, updated_at
-		FROM livestreams 
-		WHERE is_live = true
-		ORDER BY started_at DESC`
-	
-	rows, err := r.db.Query(context.Background(), query)
+		FROM livestreams
+		%s
+		%s
+		LIMIT %d`, conds.where(), key.orderBy("id"), limit+1)
+
+	rows, err := r.db.Query(ctx, query, conds.args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
-	var streams []entities.LiveStream
+
 	for rows.Next() {
 		var stream entities.LiveStream
 		err := rows.Scan(
@@ -100,26 +174,39 @@ func (r *PostgresLiveStreamRepository) GetActiveLiveStreams() ([]entities.LiveSt
 		if err != nil {
 			return nil, err
 		}
-		streams = append(streams, stream)
+		page.Data = append(page.Data, stream)
 	}
-	
-	return streams, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Data) > limit {
+		page.Data = page.Data[:limit]
+		last := page.Data[limit-1]
+		value := last.StartedAt.Format(timestampCursorLayout)
+		if sort == entities.LiveStreamSortViewers {
+			value = strconv.Itoa(last.ViewerCount)
+		}
+		page.NextCursor = entities.PageCursor{Sort: sort, Value: value, ID: last.ID}.Encode()
+	}
+
+	return page, nil
 }
 
-func (r *PostgresLiveStreamRepository) UpdateLiveStreamStatus(sellerID string, isLive bool) error {
+func (r *PostgresLiveStreamRepository) UpdateLiveStreamStatus(sellerID int, isLive bool) error {
 	query := `UPDATE livestreams SET is_live = $1, updated_at = $2 WHERE seller_id = $3 AND is_live = true`
-	_, err := r.db.Exec(context.Background(), query, isLive, time.Now(), sellerID)
+	_, err := r.db.Exec(context.Background(), query, isLive, time.Now().UTC(), sellerID)
 	return err
 }
 
-func (r *PostgresLiveStreamRepository) UpdateViewerCount(sellerID string, count int) error {
+func (r *PostgresLiveStreamRepository) UpdateViewerCount(sellerID int, count int) error {
 	query := `UPDATE livestreams SET viewer_count = $1, updated_at = $2 WHERE seller_id = $3 AND is_live = true`
-	_, err := r.db.Exec(context.Background(), query, count, time.Now(), sellerID)
+	_, err := r.db.Exec(context.Background(), query, count, time.Now().UTC(), sellerID)
 	return err
 }
 
-func (r *PostgresLiveStreamRepository) EndLiveStream(sellerID string) error {
+func (r *PostgresLiveStreamRepository) EndLiveStream(sellerID int) error {
 	query := `UPDATE livestreams SET is_live = false, ended_at = $1, updated_at = $2 WHERE seller_id = $3 AND is_live = true`
-	_, err := r.db.Exec(context.Background(), query, time.Now(), time.Now(), sellerID)
+	_, err := r.db.Exec(context.Background(), query, time.Now().UTC(), time.Now().UTC(), sellerID)
 	return err
 }
\ No newline at end of file
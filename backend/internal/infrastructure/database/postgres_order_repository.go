@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresOrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOrderRepository(db *pgxpool.Pool) repositories.OrderRepository {
+	return &postgresOrderRepository{db: db}
+}
+
+const orderColumns = `id, user_id, seller_id, livestream_id, status, total_amount, created_at, updated_at`
+
+func scanOrder(row pgx.Row, order *entities.Order) error {
+	return row.Scan(
+		&order.ID, &order.UserID, &order.SellerID, &order.LiveStreamID,
+		&order.Status, &order.TotalAmount, &order.CreatedAt, &order.UpdatedAt,
+	)
+}
+
+func (r *postgresOrderRepository) FindByID(ctx context.Context, id int) (*entities.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+
+	var order entities.Order
+	if err := scanOrder(r.db.QueryRow(ctx, query, id), &order); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	orders := []entities.Order{order}
+	if err := r.attachItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	order = orders[0]
+
+	eventsQuery := `
+		SELECT id, order_id, COALESCE(from_status, ''), to_status, actor_user_id, COALESCE(note, ''), created_at
+		FROM order_events
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(ctx, eventsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event entities.OrderEvent
+		err := rows.Scan(&event.ID, &event.OrderID, &event.FromStatus, &event.ToStatus, &event.ActorUserID, &event.Note, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		order.Events = append(order.Events, event)
+	}
+
+	return &order, rows.Err()
+}
+
+func (r *postgresOrderRepository) FindByUserID(ctx context.Context, userID int) ([]entities.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.findOrders(ctx, query, userID)
+}
+
+func (r *postgresOrderRepository) FindBySellerID(ctx context.Context, sellerID int, status string) ([]entities.Order, error) {
+	if status == "" {
+		query := `SELECT ` + orderColumns + ` FROM orders WHERE seller_id = $1 ORDER BY created_at DESC`
+		return r.findOrders(ctx, query, sellerID)
+	}
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE seller_id = $1 AND status = $2 ORDER BY created_at DESC`
+	return r.findOrders(ctx, query, sellerID, status)
+}
+
+func (r *postgresOrderRepository) UpdateStatus(ctx context.Context, event *entities.OrderEvent) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	moved, err := transitionOrder(ctx, tx, event)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return entities.ErrConflict
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresOrderRepository) findOrders(ctx context.Context, query string, args ...interface{}) ([]entities.Order, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []entities.Order{}
+	for rows.Next() {
+		var order entities.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// attachItems loads the items of all given orders with a single query.
+func (r *postgresOrderRepository) attachItems(ctx context.Context, orders []entities.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(orders))
+	index := make(map[int]int, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+		index[orders[i].ID] = i
+		orders[i].Items = []entities.OrderItem{}
+	}
+
+	query := `
+		SELECT id, order_id, product_id, variant_id, product_name, COALESCE(variant_name, ''), unit_price, quantity
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entities.OrderItem
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.VariantID, &item.ProductName, &item.VariantName, &item.UnitPrice, &item.Quantity); err != nil {
+			return err
+		}
+		i := index[item.OrderID]
+		orders[i].Items = append(orders[i].Items, item)
+	}
+	return rows.Err()
+}
+
+// insertOrder stores a new order with its items and the event recording its
+// creation. It runs inside the caller's transaction.
+func insertOrder(ctx context.Context, tx pgx.Tx, order *entities.Order) error {
+	orderQuery := `
+		INSERT INTO orders (user_id, seller_id, livestream_id, status, total_amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	err := tx.QueryRow(ctx, orderQuery, order.UserID, order.SellerID, order.LiveStreamID, order.Status, order.TotalAmount).
+		Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO order_items (order_id, product_id, variant_id, product_name, variant_name, unit_price, quantity)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		RETURNING id
+	`
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.OrderID = order.ID
+		err := tx.QueryRow(ctx, itemQuery, item.OrderID, item.ProductID, item.VariantID, item.ProductName, item.VariantName, item.UnitPrice, item.Quantity).
+			Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	event := entities.OrderEvent{OrderID: order.ID, ToStatus: order.Status, ActorUserID: &order.UserID}
+	if err := insertOrderEvent(ctx, tx, &event); err != nil {
+		return err
+	}
+	order.Events = []entities.OrderEvent{event}
+	return nil
+}
+
+// transitionOrder applies a status change if the order is still in the
+// event's from status, recording the event and restocking items when the
+// transition calls for it. It reports whether the order was moved.
+func transitionOrder(ctx context.Context, tx pgx.Tx, event *entities.OrderEvent) (bool, error) {
+	result, err := tx.Exec(ctx,
+		`UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3`,
+		event.ToStatus, event.OrderID, event.FromStatus,
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if entities.OrderTransitionRestocks(event.FromStatus, event.ToStatus) {
+		if err := restockOrder(ctx, tx, event.OrderID); err != nil {
+			return false, err
+		}
+	}
+
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func insertOrderEvent(ctx context.Context, tx pgx.Tx, event *entities.OrderEvent) error {
+	query := `
+		INSERT INTO order_events (order_id, from_status, to_status, actor_user_id, note)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at
+	`
+	return tx.QueryRow(ctx, query, event.OrderID, event.FromStatus, event.ToStatus, event.ActorUserID, event.Note).
+		Scan(&event.ID, &event.CreatedAt)
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresPaymentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPaymentRepository(db *pgxpool.Pool) repositories.PaymentRepository {
+	return &postgresPaymentRepository{db: db}
+}
+
+const paymentColumns = `id, order_id, provider, provider_payment_id, amount, currency, status,
+	COALESCE(client_secret, ''), COALESCE(checkout_url, ''), created_at, updated_at`
+
+func scanPayment(row pgx.Row) (*entities.Payment, error) {
+	var p entities.Payment
+	err := row.Scan(
+		&p.ID, &p.OrderID, &p.Provider, &p.ProviderPaymentID, &p.Amount, &p.Currency, &p.Status,
+		&p.ClientSecret, &p.CheckoutURL, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &p, nil
+}
+
+func (r *postgresPaymentRepository) Create(ctx context.Context, payment *entities.Payment) error {
+	query := `
+		INSERT INTO payments (order_id, provider, provider_payment_id, amount, currency, status, client_secret, checkout_url)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''))
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		payment.OrderID, payment.Provider, payment.ProviderPaymentID, payment.Amount, payment.Currency,
+		payment.Status, payment.ClientSecret, payment.CheckoutURL,
+	).Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
+	return mapPgError(err)
+}
+
+func (r *postgresPaymentRepository) FindByProviderPaymentID(ctx context.Context, provider string, providerPaymentID string) (*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE provider = $1 AND provider_payment_id = $2`
+	return scanPayment(r.db.QueryRow(ctx, query, provider, providerPaymentID))
+}
+
+func (r *postgresPaymentRepository) FindLatestByOrderID(ctx context.Context, orderID int) (*entities.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE order_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+	return scanPayment(r.db.QueryRow(ctx, query, orderID))
+}
+
+func (r *postgresPaymentRepository) ApplyEvent(ctx context.Context, provider string, event *entities.PaymentWebhookEvent, update *entities.PaymentUpdate) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	eventQuery := `
+		INSERT INTO payment_events (provider, event_id, payment_id, type, payload)
+		VALUES ($1, $2, $3, $4, $5::jsonb)
+		ON CONFLICT (provider, event_id) DO NOTHING
+		RETURNING id
+	`
+	var eventRowID int
+	err = tx.QueryRow(ctx, eventQuery, provider, event.EventID, update.PaymentID, event.Type, string(event.Payload)).Scan(&eventRowID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE payments SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, update.PaymentStatus, update.PaymentID)
+	if err != nil {
+		return false, err
+	}
+
+	if update.OrderEvent != nil {
+		// An order that has moved on in the meantime, e.g. been cancelled,
+		// is left alone; the payment status still records what happened.
+		if _, err := transitionOrder(ctx, tx, update.OrderEvent); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -5,23 +5,24 @@ import (
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type postgresPinnedRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewPostgresPinnedRepository(db *pgx.Conn) repositories.PinnedProductRepository {
+func NewPostgresPinnedRepository(db *pgxpool.Pool) repositories.PinnedProductRepository {
 	return &postgresPinnedRepository{db: db}
 }
 
-func (r *postgresPinnedRepository) FindPinnedBySellerID(ctx context.Context, sellerID string) ([]entities.PinnedProduct, error) {
+func (r *postgresPinnedRepository) FindPinnedBySellerID(ctx context.Context, sellerID int) ([]entities.PinnedProduct, error) {
 	query := `
-		SELECT pp.id, pp.product_id, pp.seller_id, pp.similarity_score, pp.is_pinned, pp.pinned_at,
-		       p.name, p.description, p.price
+		SELECT pp.id, pp.product_id, pp.variant_id, pp.seller_id, pp.similarity_score, pp.is_pinned, pp.pinned_at,
+		       p.name, p.description, COALESCE(v.price, p.price)
 		FROM pinned_products pp
 		JOIN products p ON pp.product_id = p.id
+		LEFT JOIN product_variants v ON pp.variant_id = v.id
 		WHERE pp.seller_id = $1 AND pp.is_pinned = true
 		ORDER BY pp.pinned_at DESC
 	`
@@ -38,7 +39,7 @@ func (r *postgresPinnedRepository) FindPinnedBySellerID(ctx context.Context, sel
 		var p entities.Product
 		
 		err := rows.Scan(
-			&pp.ID, &pp.ProductID, &pp.SellerID, &pp.SimilarityScore, &pp.IsPinned, &pp.PinnedAt,
+			&pp.ID, &pp.ProductID, &pp.VariantID, &pp.SellerID, &pp.SimilarityScore, &pp.IsPinned, &pp.PinnedAt,
 			&p.Name, &p.Description, &p.Price,
 		)
 		if err != nil {
@@ -53,6 +54,13 @@ func (r *postgresPinnedRepository) FindPinnedBySellerID(ctx context.Context, sel
 	return pinnedProducts, nil
 }
 
+func (r *postgresPinnedRepository) IsPinned(ctx context.Context, productID int, sellerID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM pinned_products WHERE product_id = $1 AND seller_id = $2 AND is_pinned = true)`
+	var pinned bool
+	err := r.db.QueryRow(ctx, query, productID, sellerID).Scan(&pinned)
+	return pinned, err
+}
+
 func (r *postgresPinnedRepository) PinProduct(ctx context.Context, pinData *entities.PinnedProduct) error {
 	unpinQuery := `UPDATE pinned_products SET is_pinned = false WHERE seller_id = $1 AND is_pinned = true`
 	_, err := r.db.Exec(ctx, unpinQuery, pinData.SellerID)
@@ -61,23 +69,78 @@ func (r *postgresPinnedRepository) PinProduct(ctx context.Context, pinData *enti
 	}
 
 	pinQuery := `
-		INSERT INTO pinned_products (product_id, seller_id, similarity_score, is_pinned, pinned_at)
-		VALUES ($1, $2, $3, true, CURRENT_TIMESTAMP)
+		INSERT INTO pinned_products (product_id, variant_id, seller_id, similarity_score, is_pinned, pinned_at)
+		VALUES ($1, $2, $3, $4, true, CURRENT_TIMESTAMP)
 		ON CONFLICT (product_id, seller_id) 
-		DO UPDATE SET similarity_score = $3, is_pinned = true, pinned_at = CURRENT_TIMESTAMP
+		DO UPDATE SET variant_id = $2, similarity_score = $4, is_pinned = true, pinned_at = CURRENT_TIMESTAMP
 		RETURNING id
 	`
 	
-	return r.db.QueryRow(ctx, pinQuery, pinData.ProductID, pinData.SellerID, pinData.SimilarityScore).Scan(&pinData.ID)
+	return r.db.QueryRow(ctx, pinQuery, pinData.ProductID, pinData.VariantID, pinData.SellerID, pinData.SimilarityScore).Scan(&pinData.ID)
+}
+
+func (r *postgresPinnedRepository) PinProductLimited(ctx context.Context, pinData *entities.PinnedProduct, maxPinned int) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialise pinning per seller so concurrent pins cannot both see room
+	// under the limit.
+	if _, err := tx.Exec(ctx, `SELECT id FROM sellers WHERE id = $1 FOR UPDATE`, pinData.SellerID); err != nil {
+		return nil, err
+	}
+
+	pinQuery := `
+		INSERT INTO pinned_products (product_id, variant_id, seller_id, similarity_score, is_pinned, pinned_at)
+		VALUES ($1, $2, $3, $4, true, CURRENT_TIMESTAMP)
+		ON CONFLICT (product_id, seller_id)
+		DO UPDATE SET variant_id = $2, similarity_score = $4, is_pinned = true, pinned_at = CURRENT_TIMESTAMP
+		RETURNING id
+	`
+	if err := tx.QueryRow(ctx, pinQuery, pinData.ProductID, pinData.VariantID, pinData.SellerID, pinData.SimilarityScore).Scan(&pinData.ID); err != nil {
+		return nil, err
+	}
+
+	evictQuery := `
+		UPDATE pinned_products SET is_pinned = false
+		WHERE seller_id = $1 AND is_pinned = true AND id NOT IN (
+			SELECT id FROM pinned_products
+			WHERE seller_id = $1 AND is_pinned = true
+			ORDER BY pinned_at DESC, id DESC
+			LIMIT $2
+		)
+		RETURNING product_id
+	`
+	rows, err := tx.Query(ctx, evictQuery, pinData.SellerID, maxPinned)
+	if err != nil {
+		return nil, err
+	}
+	unpinned := []int{}
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		unpinned = append(unpinned, productID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return unpinned, tx.Commit(ctx)
 }
 
-func (r *postgresPinnedRepository) UnpinProduct(ctx context.Context, productID int, sellerID string) error {
+func (r *postgresPinnedRepository) UnpinProduct(ctx context.Context, productID int, sellerID int) error {
 	query := `UPDATE pinned_products SET is_pinned = false WHERE product_id = $1 AND seller_id = $2`
 	_, err := r.db.Exec(ctx, query, productID, sellerID)
 	return err
 }
 
-func (r *postgresPinnedRepository) UnpinAllProducts(ctx context.Context, sellerID string) (int64, error) {
+func (r *postgresPinnedRepository) UnpinAllProducts(ctx context.Context, sellerID int) (int64, error) {
 	query := `UPDATE pinned_products SET is_pinned = false WHERE seller_id = $1 AND is_pinned = true`
 	result, err := r.db.Exec(ctx, query, sellerID)
 	if err != nil {
@@ -2,102 +2,85 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
+	"strconv"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type postgresProductRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewPostgresProductRepository(db *pgx.Conn) repositories.ProductRepository {
+func NewPostgresProductRepository(db *pgxpool.Pool) repositories.ProductRepository {
 	return &postgresProductRepository{db: db}
 }
 
-func (r *postgresProductRepository) FindAll(ctx context.Context) ([]entities.Product, error) {
+func (r *postgresProductRepository) FindByID(ctx context.Context, id int) (*entities.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.description, p.price, p.seller_id, p.created_at, p.updated_at,
-		       COALESCE(array_agg(i.image_url) FILTER (WHERE i.image_url IS NOT NULL), '{}') as image_urls
+		SELECT p.id, p.name, p.description, p.price, p.seller_id, p.stock, p.created_at, p.updated_at
 		FROM products p
-		LEFT JOIN images i ON p.id = i.product_id
-		GROUP BY p.id, p.name, p.description, p.price, p.seller_id, p.created_at, p.updated_at
-		ORDER BY p.created_at DESC
+		WHERE p.id = $1
 	`
 	
-	rows, err := r.db.Query(ctx, query)
+	var p entities.Product
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.Stock, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	
+	products := []entities.Product{p}
+	if err := r.attachImages(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (r *postgresProductRepository) FindByIDs(ctx context.Context, ids []int) ([]entities.Product, error) {
+	query := `
+		SELECT p.id, p.name, p.description, COALESCE(p.price, 0), p.seller_id, p.stock, p.created_at, p.updated_at
+		FROM products p
+		WHERE p.id = ANY($1)
+	`
+	rows, err := r.db.Query(ctx, query, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []entities.Product
+	products := []entities.Product{}
 	for rows.Next() {
 		var p entities.Product
-		var imageURLs []string
-		
-		err := rows.Scan(
-			&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.CreatedAt, &p.UpdatedAt, &imageURLs,
-		)
+		err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.Stock, &p.CreatedAt, &p.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
-		
-		for _, url := range imageURLs {
-			if url != "" {
-				p.Images = append(p.Images, entities.Image{ImageURL: url})
-			}
-		}
-		
 		products = append(products, p)
 	}
-
-	return products, nil
-}
-
-func (r *postgresProductRepository) FindByID(ctx context.Context, id int) (*entities.Product, error) {
-	query := `
-		SELECT p.id, p.name, p.description, p.price, p.seller_id, p.created_at, p.updated_at
-		FROM products p
-		WHERE p.id = $1
-	`
-	
-	var p entities.Product
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.CreatedAt, &p.UpdatedAt,
-	)
-	if err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	
-	imageQuery := `SELECT id, image_url FROM images WHERE product_id = $1`
-	imageRows, err := r.db.Query(ctx, imageQuery, id)
-	if err != nil {
+
+	if err := r.attachImages(ctx, products); err != nil {
 		return nil, err
 	}
-	defer imageRows.Close()
-	
-	for imageRows.Next() {
-		var img entities.Image
-		imageRows.Scan(&img.ID, &img.ImageURL)
-		img.ProductID = id
-		p.Images = append(p.Images, img)
-	}
-	
-	return &p, nil
+	return products, nil
 }
 
 func (r *postgresProductRepository) Create(ctx context.Context, product *entities.Product) error {
 	query := `
-		INSERT INTO products (name, description, price, seller_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO products (name, description, price, seller_id, stock)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at, updated_at
 	`
 	
 	return r.db.QueryRow(
 		ctx, query,
-		product.Name, product.Description, product.Price, product.SellerID,
+		product.Name, product.Description, product.Price, product.SellerID, product.Stock,
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
 }
 
@@ -106,13 +89,13 @@ func (r *postgresProductRepository) Update(ctx context.Context, product *entitie
 		UPDATE products 
 		SET name = $1, description = $2, price = $3, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $4
-		RETURNING updated_at
+		RETURNING stock, updated_at
 	`
 	
 	return r.db.QueryRow(
 		ctx, query,
 		product.Name, product.Description, product.Price, product.ID,
-	).Scan(&product.UpdatedAt)
+	).Scan(&product.Stock, &product.UpdatedAt)
 }
 
 func (r *postgresProductRepository) Delete(ctx context.Context, id int) error {
@@ -121,45 +104,212 @@ func (r *postgresProductRepository) Delete(ctx context.Context, id int) error {
 	return err
 }
 
-func (r *postgresProductRepository) FindBySellerID(ctx context.Context, sellerID int) ([]entities.Product, error) {
-	query := `
-		SELECT p.id, p.name, p.description, p.price, p.seller_id, p.created_at, p.updated_at,
-		       COALESCE(array_agg(i.image_url) FILTER (WHERE i.image_url IS NOT NULL), '{}') as image_urls
+var productSortKeys = map[string]sortKey{
+	entities.ProductSortNewest:    {expr: "p.created_at", cast: "timestamp", desc: true},
+	entities.ProductSortOldest:    {expr: "p.created_at", cast: "timestamp"},
+	entities.ProductSortPriceAsc:  {expr: "COALESCE(p.price, 0)", cast: "numeric"},
+	entities.ProductSortPriceDesc: {expr: "COALESCE(p.price, 0)", cast: "numeric", desc: true},
+	entities.ProductSortName:      {expr: "p.name", cast: "text"},
+}
+
+func (r *postgresProductRepository) List(ctx context.Context, filter *entities.ProductFilter) (*entities.ProductPage, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = entities.ProductSortNewest
+	}
+	key, ok := productSortKeys[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort %q", entities.ErrInvalidInput, sort)
+	}
+
+	var conds conditions
+	if filter.SellerID != nil {
+		conds.add("p.seller_id = ?", *filter.SellerID)
+	}
+	if filter.MinPrice != nil {
+		conds.add("p.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conds.add("p.price <= ?", *filter.MaxPrice)
+	}
+	if filter.CreatedAfter != nil {
+		conds.add("p.created_at > ?::timestamp", filter.CreatedAfter.UTC().Format(timestampCursorLayout))
+	}
+	if filter.HasImages != nil {
+		hasImages := "EXISTS (SELECT 1 FROM images i WHERE i.product_id = p.id)"
+		if !*filter.HasImages {
+			hasImages = "NOT " + hasImages
+		}
+		conds.add(hasImages)
+	}
+
+	page := &entities.ProductPage{Data: []entities.Product{}}
+	countQuery := `SELECT COUNT(*) FROM products p ` + conds.where()
+	if err := r.db.QueryRow(ctx, countQuery, conds.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := entities.DecodePageCursor(filter.Cursor, sort)
+		if err != nil {
+			return nil, err
+		}
+		key.after(&conds, "p.id", cursor)
+	}
+
+	// One extra row tells whether there is a next page.
+	limit := entities.PageLimit(filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT p.id, p.name, p.description, COALESCE(p.price, 0), p.seller_id, p.stock, p.created_at, p.updated_at
 		FROM products p
-		LEFT JOIN images i ON p.id = i.product_id
-		WHERE p.seller_id = $1
-		GROUP BY p.id, p.name, p.description, p.price, p.seller_id, p.created_at, p.updated_at
-		ORDER BY p.created_at DESC
-	`
-	
-	rows, err := r.db.Query(ctx, query, sellerID)
+		%s
+		%s
+		LIMIT %d
+	`, conds.where(), key.orderBy("p.id"), limit+1)
+
+	rows, err := r.db.Query(ctx, query, conds.args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []entities.Product
 	for rows.Next() {
 		var p entities.Product
-		var imageURLs []string
-		
+		err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.Stock, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		page.Data = append(page.Data, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Data) > limit {
+		page.Data = page.Data[:limit]
+		last := page.Data[limit-1]
+		page.NextCursor = entities.PageCursor{Sort: sort, Value: productSortValue(sort, &last), ID: last.ID}.Encode()
+	}
+
+	if err := r.attachImages(ctx, page.Data); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func productSortValue(sort string, p *entities.Product) string {
+	switch sort {
+	case entities.ProductSortPriceAsc, entities.ProductSortPriceDesc:
+		return strconv.FormatFloat(p.Price, 'f', -1, 64)
+	case entities.ProductSortName:
+		return p.Name
+	default:
+		return p.CreatedAt.Format(timestampCursorLayout)
+	}
+}
+
+// attachImages loads the images of all given products with a single query.
+func (r *postgresProductRepository) attachImages(ctx context.Context, products []entities.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	index := make(map[int]int, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+		index[products[i].ID] = i
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT id, product_id, variant_id, image_url FROM images WHERE product_id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img entities.Image
+		if err := rows.Scan(&img.ID, &img.ProductID, &img.VariantID, &img.ImageURL); err != nil {
+			return err
+		}
+		i := index[img.ProductID]
+		products[i].Images = append(products[i].Images, img)
+	}
+	return rows.Err()
+}
+
+// Search matches products against a to_tsquery expression, best ranked
+// first. Names are highlighted in full and descriptions as short snippets.
+func (r *postgresProductRepository) Search(ctx context.Context, tsQuery string, params *entities.ProductSearchParams) (*entities.ProductSearchResponse, error) {
+	var conds conditions
+	conds.add("p.search_vector @@ to_tsquery('simple', ?)", tsQuery)
+	if params.SellerID != nil {
+		conds.add("p.seller_id = ?", *params.SellerID)
+	}
+	if params.LiveStreamID != nil {
+		conds.add(`EXISTS (
+			SELECT 1 FROM pinned_products pp
+			JOIN livestreams l ON l.seller_id = pp.seller_id AND l.is_live = true
+			WHERE pp.product_id = p.id AND pp.is_pinned = true AND l.id = ?
+		)`, *params.LiveStreamID)
+	} else if params.PinnedLive {
+		conds.add(`EXISTS (
+			SELECT 1 FROM pinned_products pp
+			JOIN livestreams l ON l.seller_id = pp.seller_id AND l.is_live = true
+			WHERE pp.product_id = p.id AND pp.is_pinned = true
+		)`)
+	}
+
+	result := &entities.ProductSearchResponse{Data: []entities.ProductSearchHit{}}
+	countQuery := `SELECT COUNT(*) FROM products p ` + conds.where()
+	if err := r.db.QueryRow(ctx, countQuery, conds.args...).Scan(&result.Total); err != nil {
+		return nil, err
+	}
+
+	// $1 is always the tsquery added first above.
+	query := fmt.Sprintf(`
+		SELECT p.id, p.name, p.description, COALESCE(p.price, 0), p.seller_id, p.stock, p.created_at, p.updated_at,
+		       ts_rank(p.search_vector, to_tsquery('simple', $1)),
+		       ts_headline('simple', p.name, to_tsquery('simple', $1), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+		       ts_headline('simple', COALESCE(p.description, ''), to_tsquery('simple', $1),
+		                   'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=10, MaxFragments=2')
+		FROM products p
+		%s
+		ORDER BY 9 DESC, p.id DESC
+		LIMIT %d
+	`, conds.where(), entities.PageLimit(params.Limit))
+
+	rows, err := r.db.Query(ctx, query, conds.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []entities.Product{}
+	for rows.Next() {
+		var hit entities.ProductSearchHit
+		p := &hit.Product
 		err := rows.Scan(
-			&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.CreatedAt, &p.UpdatedAt, &imageURLs,
+			&p.ID, &p.Name, &p.Description, &p.Price, &p.SellerID, &p.Stock, &p.CreatedAt, &p.UpdatedAt,
+			&hit.Rank, &hit.NameHighlight, &hit.Snippet,
 		)
 		if err != nil {
 			return nil, err
 		}
-		
-		for _, url := range imageURLs {
-			if url != "" {
-				p.Images = append(p.Images, entities.Image{ImageURL: url})
-			}
-		}
-		
-		products = append(products, p)
+		result.Data = append(result.Data, hit)
+		products = append(products, hit.Product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return products, nil
+	if err := r.attachImages(ctx, products); err != nil {
+		return nil, err
+	}
+	for i := range result.Data {
+		result.Data[i].Product.Images = products[i].Images
+	}
+	return result, nil
 }
 
 func (r *postgresProductRepository) AddImages(ctx context.Context, productID int, imageURLs []string) error {
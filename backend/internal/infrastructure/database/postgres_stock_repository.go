@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresStockRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStockRepository(db *pgxpool.Pool) repositories.StockRepository {
+	return &postgresStockRepository{db: db}
+}
+
+func (r *postgresStockRepository) FindLevels(ctx context.Context, productIDs []int) ([]entities.StockLevel, error) {
+	// Products with variants keep their stock on the variants, so the
+	// product-level figure is the variants' total.
+	productQuery := `
+		SELECT p.id, p.seller_id,
+		       COALESCE((SELECT SUM(v.stock) FROM product_variants v WHERE v.product_id = p.id), p.stock),
+		       COALESCE((
+		           SELECT SUM(sr.quantity) FROM stock_reservations sr
+		           WHERE sr.product_id = p.id AND sr.expires_at > CURRENT_TIMESTAMP
+		       ), 0)
+		FROM products p
+		WHERE p.id = ANY($1)
+		ORDER BY p.id
+	`
+	levels, err := r.scanLevels(ctx, productQuery, productIDs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	variantQuery := `
+		SELECT v.product_id, v.id, p.seller_id, v.stock, COALESCE(SUM(sr.quantity), 0)
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		LEFT JOIN stock_reservations sr ON sr.variant_id = v.id AND sr.expires_at > CURRENT_TIMESTAMP
+		WHERE v.product_id = ANY($1)
+		GROUP BY v.product_id, v.id, p.seller_id, v.stock
+		ORDER BY v.product_id, v.id
+	`
+	variantLevels, err := r.scanLevels(ctx, variantQuery, productIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(levels, variantLevels...), nil
+}
+
+func (r *postgresStockRepository) scanLevels(ctx context.Context, query string, productIDs []int, withVariant bool) ([]entities.StockLevel, error) {
+	rows, err := r.db.Query(ctx, query, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []entities.StockLevel
+	for rows.Next() {
+		var level entities.StockLevel
+		if withVariant {
+			var variantID int
+			err = rows.Scan(&level.ProductID, &variantID, &level.SellerID, &level.Stock, &level.Reserved)
+			level.VariantID = &variantID
+		} else {
+			err = rows.Scan(&level.ProductID, &level.SellerID, &level.Stock, &level.Reserved)
+		}
+		if err != nil {
+			return nil, err
+		}
+		level.Available = level.Stock - level.Reserved
+		if level.Available < 0 {
+			level.Available = 0
+		}
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+func (r *postgresStockRepository) SetStock(ctx context.Context, productID int, stock int) error {
+	query := `UPDATE products SET stock = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := r.db.Exec(ctx, query, stock, productID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresStockRepository) SetVariantStock(ctx context.Context, variantID int, stock int) error {
+	query := `UPDATE product_variants SET stock = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := r.db.Exec(ctx, query, stock, variantID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresStockRepository) ReleaseExpired(ctx context.Context) ([]int, error) {
+	query := `
+		DELETE FROM cart_items
+		WHERE id IN (SELECT cart_item_id FROM stock_reservations WHERE expires_at <= CURRENT_TIMESTAMP)
+		RETURNING product_id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[int]bool)
+	var productIDs []int
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			return nil, err
+		}
+		if !seen[productID] {
+			seen[productID] = true
+			productIDs = append(productIDs, productID)
+		}
+	}
+	return productIDs, rows.Err()
+}
+
+// stockKey identifies what stock is drawn from: a variant when VariantID is
+// set and the product itself otherwise.
+type stockKey struct {
+	ProductID int
+	VariantID int
+}
+
+func newStockKey(productID int, variantID *int) stockKey {
+	key := stockKey{ProductID: productID}
+	if variantID != nil {
+		key.VariantID = *variantID
+	}
+	return key
+}
+
+// reserveStock sets the reservation held by a cart item to quantity units
+// for ttl. The product or variant row is locked first so concurrent
+// reservations for the same stock are serialised and can never oversell.
+func reserveStock(ctx context.Context, tx pgx.Tx, cartItemID int, key stockKey, quantity int, ttl time.Duration) error {
+	var stock int
+	var err error
+	if key.VariantID != 0 {
+		err = tx.QueryRow(ctx, `SELECT stock FROM product_variants WHERE id = $1 FOR UPDATE`, key.VariantID).Scan(&stock)
+	} else {
+		err = tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 FOR UPDATE`, key.ProductID).Scan(&stock)
+	}
+	if err != nil {
+		return mapPgError(err)
+	}
+
+	var reserved int
+	reservedQuery := `
+		SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations
+		WHERE product_id = $1 AND COALESCE(variant_id, 0) = $2
+		  AND cart_item_id <> $3 AND expires_at > CURRENT_TIMESTAMP
+	`
+	if err := tx.QueryRow(ctx, reservedQuery, key.ProductID, key.VariantID, cartItemID).Scan(&reserved); err != nil {
+		return err
+	}
+
+	if available := stock - reserved; available < quantity {
+		if available < 0 {
+			available = 0
+		}
+		return fmt.Errorf("%w: only %d left", entities.ErrInsufficientStock, available)
+	}
+
+	upsert := `
+		INSERT INTO stock_reservations (cart_item_id, product_id, variant_id, quantity, expires_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4, CURRENT_TIMESTAMP + make_interval(secs => $5))
+		ON CONFLICT (cart_item_id)
+		DO UPDATE SET quantity = EXCLUDED.quantity, expires_at = EXCLUDED.expires_at
+	`
+	_, err = tx.Exec(ctx, upsert, cartItemID, key.ProductID, key.VariantID, quantity, ttl.Seconds())
+	return err
+}
+
+// deductStock takes ordered quantities out of stock at checkout. The
+// caller's own reservations must already be gone so they are not counted
+// against it.
+func deductStock(ctx context.Context, tx pgx.Tx, key stockKey, quantity int) error {
+	query := `
+		UPDATE products SET stock = stock - $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND stock - $2 >= (
+			SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations
+			WHERE product_id = $1 AND variant_id IS NULL AND expires_at > CURRENT_TIMESTAMP
+		)
+	`
+	args := []interface{}{key.ProductID, quantity}
+	if key.VariantID != 0 {
+		query = `
+			UPDATE product_variants SET stock = stock - $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND stock - $2 >= (
+				SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations
+				WHERE variant_id = $1 AND expires_at > CURRENT_TIMESTAMP
+			)
+		`
+		args[0] = key.VariantID
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: product %d", entities.ErrInsufficientStock, key.ProductID)
+	}
+	return nil
+}
+
+// restockOrder returns an order's items to stock, crediting the variant an
+// item was bought as when it still exists.
+func restockOrder(ctx context.Context, tx pgx.Tx, orderID int) error {
+	variantQuery := `
+		UPDATE product_variants v SET stock = v.stock + oi.quantity, updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT variant_id, SUM(quantity) AS quantity FROM order_items
+			WHERE order_id = $1 AND variant_id IS NOT NULL
+			GROUP BY variant_id
+		) oi
+		WHERE v.id = oi.variant_id
+	`
+	if _, err := tx.Exec(ctx, variantQuery, orderID); err != nil {
+		return err
+	}
+
+	productQuery := `
+		UPDATE products p SET stock = p.stock + oi.quantity, updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT product_id, SUM(quantity) AS quantity FROM order_items
+			WHERE order_id = $1 AND product_id IS NOT NULL AND variant_id IS NULL
+			GROUP BY product_id
+		) oi
+		WHERE p.id = oi.product_id
+	`
+	_, err := tx.Exec(ctx, productQuery, orderID)
+	return err
+}
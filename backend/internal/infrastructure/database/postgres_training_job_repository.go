@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresTrainingJobRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTrainingJobRepository(db *pgxpool.Pool) repositories.TrainingJobRepository {
+	return &postgresTrainingJobRepository{db: db}
+}
+
+const trainingJobColumns = `id, seller_id, product_id, fine_tune, status, progress, message, error, created_at, started_at, finished_at, updated_at`
+
+// Training jobs are only listed newest first.
+const trainingJobSort = "newest"
+
+var trainingJobSortKey = sortKey{expr: "created_at", cast: "timestamp", desc: true}
+
+func scanTrainingJob(row pgx.Row) (*entities.TrainingJob, error) {
+	var job entities.TrainingJob
+	err := row.Scan(
+		&job.ID, &job.SellerID, &job.ProductID, &job.FineTune, &job.Status, &job.Progress, &job.Message, &job.Error,
+		&job.CreatedAt, &job.StartedAt, &job.FinishedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *postgresTrainingJobRepository) Create(ctx context.Context, job *entities.TrainingJob, runnerID string, lease time.Duration) error {
+	// The partial unique index on active jobs turns a second job for the
+	// same seller into a unique violation.
+	query := `
+		INSERT INTO training_jobs (seller_id, product_id, fine_tune, status, message, runner_id, lease_until)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP + make_interval(secs => $7))
+		RETURNING ` + trainingJobColumns
+
+	created, err := scanTrainingJob(r.db.QueryRow(ctx, query,
+		job.SellerID, job.ProductID, job.FineTune, entities.TrainingJobQueued, job.Message, runnerID, lease.Seconds(),
+	))
+	if err != nil {
+		return mapPgError(err)
+	}
+	*job = *created
+	return nil
+}
+
+func (r *postgresTrainingJobRepository) FindByID(ctx context.Context, id int) (*entities.TrainingJob, error) {
+	query := `SELECT ` + trainingJobColumns + ` FROM training_jobs WHERE id = $1`
+
+	job, err := scanTrainingJob(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return job, nil
+}
+
+func (r *postgresTrainingJobRepository) List(ctx context.Context, sellerID int, filter *entities.TrainingJobFilter) (*entities.TrainingJobPage, error) {
+	var conds conditions
+	conds.add("seller_id = ?", sellerID)
+	if filter.Status != "" {
+		conds.add("status = ?", filter.Status)
+	}
+	if filter.Cursor != "" {
+		cursor, err := entities.DecodePageCursor(filter.Cursor, trainingJobSort)
+		if err != nil {
+			return nil, err
+		}
+		trainingJobSortKey.after(&conds, "id", cursor)
+	}
+
+	// One extra row tells whether there is a next page.
+	limit := entities.PageLimit(filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM training_jobs
+		%s
+		%s
+		LIMIT %d`, trainingJobColumns, conds.where(), trainingJobSortKey.orderBy("id"), limit+1)
+
+	rows, err := r.db.Query(ctx, query, conds.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &entities.TrainingJobPage{Data: []entities.TrainingJob{}}
+	for rows.Next() {
+		job, err := scanTrainingJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Data = append(page.Data, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Data) > limit {
+		page.Data = page.Data[:limit]
+		last := page.Data[limit-1]
+		page.NextCursor = entities.PageCursor{
+			Sort:  trainingJobSort,
+			Value: last.CreatedAt.Format(timestampCursorLayout),
+			ID:    last.ID,
+		}.Encode()
+	}
+
+	return page, nil
+}
+
+func (r *postgresTrainingJobRepository) ClaimAbandoned(ctx context.Context, runnerID string, lease time.Duration) ([]entities.TrainingJob, error) {
+	// Replicas claiming at the same time block on the same rows; the one
+	// that waited re-checks the lease and skips what the other took.
+	query := `
+		WITH claimed AS (
+			UPDATE training_jobs
+			SET runner_id = $1, lease_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			WHERE status IN ('queued', 'running')
+			  AND (lease_until IS NULL OR lease_until < CURRENT_TIMESTAMP)
+			RETURNING ` + trainingJobColumns + `
+		)
+		SELECT ` + trainingJobColumns + ` FROM claimed ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, runnerID, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []entities.TrainingJob{}
+	for rows.Next() {
+		job, err := scanTrainingJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *postgresTrainingJobRepository) RenewLease(ctx context.Context, id int, runnerID string, lease time.Duration) (bool, error) {
+	query := `
+		UPDATE training_jobs
+		SET lease_until = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		WHERE id = $1 AND runner_id = $2 AND status IN ('queued', 'running')
+	`
+	result, err := r.db.Exec(ctx, query, id, runnerID, lease.Seconds())
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *postgresTrainingJobRepository) Update(ctx context.Context, job *entities.TrainingJob) error {
+	// started_at is set the first time the job is seen running and
+	// finished_at when it completes or fails.
+	query := `
+		UPDATE training_jobs
+		SET status = $1, progress = $2, message = $3, error = $4,
+		    started_at = CASE WHEN $1 = 'queued' THEN started_at ELSE COALESCE(started_at, CURRENT_TIMESTAMP) END,
+		    finished_at = CASE WHEN $1 IN ('completed', 'failed') THEN COALESCE(finished_at, CURRENT_TIMESTAMP) END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING started_at, finished_at, updated_at
+	`
+	return mapPgError(r.db.QueryRow(ctx, query,
+		job.Status, job.Progress, job.Message, job.Error, job.ID,
+	).Scan(&job.StartedAt, &job.FinishedAt, &job.UpdatedAt))
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresUserRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresUserRepository(db *pgxpool.Pool) repositories.UserRepository {
+	return &postgresUserRepository{db: db}
+}
+
+// Create inserts the user and, when user.Seller is set, its seller account in
+// the same transaction.
+func (r *postgresUserRepository) Create(ctx context.Context, user *entities.User) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO users (email, name, password_hash)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, user.Email, user.Name, user.PasswordHash).
+		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return mapPgError(err)
+	}
+
+	if user.Seller != nil {
+		user.Seller.UserID = user.ID
+		if err := insertSeller(ctx, tx, user.Seller); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresUserRepository) FindByID(ctx context.Context, id int) (*entities.User, error) {
+	return r.findOne(ctx, `WHERE u.id = $1`, id)
+}
+
+func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.findOne(ctx, `WHERE lower(u.email) = lower($1)`, email)
+}
+
+func (r *postgresUserRepository) findOne(ctx context.Context, where string, arg interface{}) (*entities.User, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.password_hash, u.created_at, u.updated_at,
+		       s.id, s.shop_name, s.created_at, s.updated_at
+		FROM users u
+		LEFT JOIN sellers s ON s.user_id = u.id
+		` + where
+
+	var u entities.User
+	var sellerID *int
+	var shopName *string
+	var sellerCreatedAt, sellerUpdatedAt *time.Time
+
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt,
+		&sellerID, &shopName, &sellerCreatedAt, &sellerUpdatedAt,
+	)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+
+	if sellerID != nil {
+		u.Seller = &entities.Seller{
+			ID:        *sellerID,
+			UserID:    u.ID,
+			ShopName:  *shopName,
+			CreatedAt: *sellerCreatedAt,
+			UpdatedAt: *sellerUpdatedAt,
+		}
+	}
+
+	return &u, nil
+}
+
+type postgresSellerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresSellerRepository(db *pgxpool.Pool) repositories.SellerRepository {
+	return &postgresSellerRepository{db: db}
+}
+
+func (r *postgresSellerRepository) Create(ctx context.Context, seller *entities.Seller) error {
+	return insertSeller(ctx, r.db, seller)
+}
+
+func (r *postgresSellerRepository) FindByID(ctx context.Context, id int) (*entities.Seller, error) {
+	return r.findOne(ctx, `WHERE id = $1`, id)
+}
+
+func (r *postgresSellerRepository) FindByUserID(ctx context.Context, userID int) (*entities.Seller, error) {
+	return r.findOne(ctx, `WHERE user_id = $1`, userID)
+}
+
+func (r *postgresSellerRepository) findOne(ctx context.Context, where string, arg interface{}) (*entities.Seller, error) {
+	query := `SELECT id, user_id, shop_name, created_at, updated_at FROM sellers ` + where
+
+	var s entities.Seller
+	err := r.db.QueryRow(ctx, query, arg).Scan(&s.ID, &s.UserID, &s.ShopName, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &s, nil
+}
+
+type postgresRefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRefreshTokenRepository(db *pgxpool.Pool) repositories.RefreshTokenRepository {
+	return &postgresRefreshTokenRepository{db: db}
+}
+
+func (r *postgresRefreshTokenRepository) Create(ctx context.Context, userID int, tokenID string, expiresAt time.Time) error {
+	query := `INSERT INTO refresh_tokens (token_id, user_id, expires_at) VALUES ($1, $2, $3)`
+	_, err := r.db.Exec(ctx, query, tokenID, userID, expiresAt)
+	return err
+}
+
+func (r *postgresRefreshTokenRepository) Revoke(ctx context.Context, tokenID string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE token_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`
+	result, err := r.db.Exec(ctx, query, tokenID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *postgresRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func insertSeller(ctx context.Context, db queryRower, seller *entities.Seller) error {
+	query := `
+		INSERT INTO sellers (user_id, shop_name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	err := db.QueryRow(ctx, query, seller.UserID, seller.ShopName).
+		Scan(&seller.ID, &seller.CreatedAt, &seller.UpdatedAt)
+	return mapPgError(err)
+}
+
+// mapPgError translates driver errors the services care about into domain
+// errors and passes everything else through.
+func mapPgError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return entities.ErrConflict
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresVariantRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresVariantRepository(db *pgxpool.Pool) repositories.VariantRepository {
+	return &postgresVariantRepository{db: db}
+}
+
+func (r *postgresVariantRepository) FindOptions(ctx context.Context, productID int) ([]entities.ProductOption, error) {
+	query := `
+		SELECT id, product_id, name, option_values, position
+		FROM product_options
+		WHERE product_id = $1
+		ORDER BY position, id
+	`
+	rows, err := r.db.Query(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := []entities.ProductOption{}
+	for rows.Next() {
+		var option entities.ProductOption
+		if err := rows.Scan(&option.ID, &option.ProductID, &option.Name, &option.Values, &option.Position); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+func (r *postgresVariantRepository) ReplaceOptions(ctx context.Context, productID int, options []entities.ProductOption) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_options WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO product_options (product_id, name, option_values, position)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	for i := range options {
+		option := &options[i]
+		option.ProductID = productID
+		option.Position = i
+		if err := tx.QueryRow(ctx, query, productID, option.Name, option.Values, option.Position).Scan(&option.ID); err != nil {
+			return mapPgError(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *postgresVariantRepository) FindByProductID(ctx context.Context, productID int) ([]entities.ProductVariant, error) {
+	query := `
+		SELECT v.id, v.product_id, COALESCE(v.sku, ''), v.options, v.price, v.stock,
+		       v.stock - COALESCE((
+		           SELECT SUM(sr.quantity) FROM stock_reservations sr
+		           WHERE sr.variant_id = v.id AND sr.expires_at > CURRENT_TIMESTAMP
+		       ), 0),
+		       v.created_at, v.updated_at
+		FROM product_variants v
+		WHERE v.product_id = $1
+		ORDER BY v.id
+	`
+	rows, err := r.db.Query(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []entities.ProductVariant{}
+	index := make(map[int]int)
+	for rows.Next() {
+		var v entities.ProductVariant
+		err := rows.Scan(&v.ID, &v.ProductID, &v.SKU, &v.Options, &v.Price, &v.Stock, &v.Available, &v.CreatedAt, &v.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if v.Available < 0 {
+			v.Available = 0
+		}
+		index[v.ID] = len(variants)
+		variants = append(variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	imageRows, err := r.db.Query(ctx, `SELECT id, variant_id, image_url FROM images WHERE product_id = $1 AND variant_id IS NOT NULL ORDER BY id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer imageRows.Close()
+
+	for imageRows.Next() {
+		var img entities.Image
+		var variantID int
+		if err := imageRows.Scan(&img.ID, &variantID, &img.ImageURL); err != nil {
+			return nil, err
+		}
+		img.ProductID = productID
+		img.VariantID = &variantID
+		if i, ok := index[variantID]; ok {
+			variants[i].Images = append(variants[i].Images, img)
+		}
+	}
+
+	return variants, imageRows.Err()
+}
+
+func (r *postgresVariantRepository) FindByID(ctx context.Context, id int) (*entities.ProductVariant, error) {
+	query := `
+		SELECT id, product_id, COALESCE(sku, ''), options, price, stock, created_at, updated_at
+		FROM product_variants
+		WHERE id = $1
+	`
+	var v entities.ProductVariant
+	err := r.db.QueryRow(ctx, query, id).Scan(&v.ID, &v.ProductID, &v.SKU, &v.Options, &v.Price, &v.Stock, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	return &v, nil
+}
+
+func (r *postgresVariantRepository) Create(ctx context.Context, variant *entities.ProductVariant) error {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO product_variants (product_id, sku, options, price, stock)
+		VALUES ($1, NULLIF($2, ''), $3::jsonb, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	err = r.db.QueryRow(ctx, query, variant.ProductID, variant.SKU, string(options), variant.Price, variant.Stock).
+		Scan(&variant.ID, &variant.CreatedAt, &variant.UpdatedAt)
+	return mapPgError(err)
+}
+
+func (r *postgresVariantRepository) Update(ctx context.Context, variant *entities.ProductVariant) error {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE product_variants
+		SET sku = NULLIF($1, ''), options = $2::jsonb, price = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING stock, updated_at
+	`
+	err = r.db.QueryRow(ctx, query, variant.SKU, string(options), variant.Price, variant.ID).
+		Scan(&variant.Stock, &variant.UpdatedAt)
+	return mapPgError(err)
+}
+
+func (r *postgresVariantRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM product_variants WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresVariantRepository) AddImages(ctx context.Context, productID int, variantID int, imageURLs []string) error {
+	for _, url := range imageURLs {
+		query := `INSERT INTO images (product_id, variant_id, image_url) VALUES ($1, $2, $3)`
+		if _, err := r.db.Exec(ctx, query, productID, variantID, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
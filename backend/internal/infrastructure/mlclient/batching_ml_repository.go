@@ -0,0 +1,120 @@
+package mlclient
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"sync"
+	"time"
+)
+
+// batchingMLRepository coalesces stream frames of the same seller that
+// arrive within a short window into one /predict-batch request. Everything
+// else, including one-off PredictProduct calls that carry their own
+// settings, goes straight to the wrapped repository.
+type batchingMLRepository struct {
+	repositories.MLRepository
+
+	window    time.Duration
+	maxFrames int
+
+	mu      sync.Mutex
+	pending map[int]*frameBatch
+}
+
+// frameBatch collects the frames of one seller until it is sent. waiters[i]
+// receives the outcome of frames[i]. The batch is predicted with the
+// settings its first frame came with, so a change to them applies from the
+// next batch.
+type frameBatch struct {
+	settings *entities.DetectionSettings
+	frames   [][]byte
+	waiters  []chan frameOutcome
+}
+
+type frameOutcome struct {
+	result *entities.PredictionResponse
+	err    error
+}
+
+// NewBatchingMLRepository wraps next so concurrent stream frames are sent in
+// batches. ML_BATCH_WINDOW sets how long a frame waits for
+// others to join it; unless it is set, next is returned unchanged.
+func NewBatchingMLRepository(next repositories.MLRepository) repositories.MLRepository {
+	window := envDuration("ML_BATCH_WINDOW", 0)
+	maxFrames := envInt("ML_BATCH_MAX_FRAMES", 8)
+	if maxFrames > entities.MaxBatchFrames {
+		maxFrames = entities.MaxBatchFrames
+	}
+	if window <= 0 || maxFrames < 2 {
+		return next
+	}
+
+	return &batchingMLRepository{
+		MLRepository: next,
+		window:       window,
+		maxFrames:    maxFrames,
+		pending:      make(map[int]*frameBatch),
+	}
+}
+
+func (r *batchingMLRepository) ProcessStreamFrame(ctx context.Context, sellerID int, imageData []byte, settings *entities.DetectionSettings) (*entities.PredictionResponse, error) {
+	outcome := make(chan frameOutcome, 1)
+
+	r.mu.Lock()
+	batch, ok := r.pending[sellerID]
+	if !ok {
+		batch = &frameBatch{settings: settings}
+		r.pending[sellerID] = batch
+		time.AfterFunc(r.window, func() { r.flush(sellerID, batch) })
+	}
+	batch.frames = append(batch.frames, imageData)
+	batch.waiters = append(batch.waiters, outcome)
+	full := len(batch.frames) >= r.maxFrames
+	r.mu.Unlock()
+
+	if full {
+		go r.flush(sellerID, batch)
+	}
+
+	select {
+	case o := <-outcome:
+		return o.result, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush sends the batch unless a full batch was sent before its window
+// ended.
+func (r *batchingMLRepository) flush(sellerID int, batch *frameBatch) {
+	r.mu.Lock()
+	if r.pending[sellerID] != batch {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.pending, sellerID)
+	r.mu.Unlock()
+
+	// The batch is shared by callers who may give up at different times, so
+	// it runs under the client's own timeout rather than any one of theirs.
+	ctx := context.Background()
+
+	if len(batch.frames) == 1 {
+		result, err := r.MLRepository.ProcessStreamFrame(ctx, sellerID, batch.frames[0], batch.settings)
+		batch.waiters[0] <- frameOutcome{result: result, err: err}
+		return
+	}
+
+	results, err := r.MLRepository.PredictBatch(ctx, sellerID, batch.frames, batch.settings)
+	for i, waiter := range batch.waiters {
+		switch {
+		case err != nil:
+			waiter <- frameOutcome{err: err}
+		case results[i].Error != "":
+			waiter <- frameOutcome{err: predictError(results[i].Error)}
+		default:
+			waiter <- frameOutcome{result: results[i].PredictionResponse}
+		}
+	}
+}
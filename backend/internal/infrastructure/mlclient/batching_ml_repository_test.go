@@ -0,0 +1,96 @@
+package mlclient_test
+
+import (
+	"context"
+	"live-shopping-ai/backend/internal/infrastructure/mlclient"
+	"live-shopping-ai/backend/internal/infrastructure/mlclient/mlfake"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBatchingCoalescesConcurrentFrames(t *testing.T) {
+	server, client := newClient(t, map[string]string{
+		"ML_BATCH_WINDOW":     "1h",
+		"ML_BATCH_MAX_FRAMES": "3",
+	})
+	server.SetPredictions(7, []mlfake.Prediction{{ProductID: "42"}}, nil)
+	batching := mlclient.NewBatchingMLRepository(client)
+
+	frames := [][]byte{[]byte("one"), {}, []byte("three")}
+	errs := make([]error, len(frames))
+	var wg sync.WaitGroup
+	for i := range frames {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := batching.ProcessStreamFrame(context.Background(), 7, frames[i], nil)
+			if err == nil && (len(result.Predictions) != 1 || result.Predictions[0].ProductID != "42") {
+				t.Errorf("frame %d: result = %+v", i, result)
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	// A full batch is sent without waiting out the window.
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Path != "/predict-batch" {
+		t.Fatalf("requests = %+v, want one to /predict-batch", requests)
+	}
+	if len(requests[0].Files) != len(frames) {
+		t.Errorf("batch carried %d frames, want %d", len(requests[0].Files), len(frames))
+	}
+
+	for i, err := range errs {
+		empty := len(frames[i]) == 0
+		switch {
+		case empty && (err == nil || !strings.Contains(err.Error(), mlfake.UnreadableFrameMessage)):
+			t.Errorf("frame %d: error = %v, want the frame's own error", i, err)
+		case !empty && err != nil:
+			t.Errorf("frame %d: %v", i, err)
+		}
+	}
+}
+
+func TestBatchingSendsLoneFrameOnItsOwn(t *testing.T) {
+	server, client := newClient(t, map[string]string{"ML_BATCH_WINDOW": "10ms"})
+	server.SetPredictions(7, []mlfake.Prediction{{ProductID: "42"}}, nil)
+	batching := mlclient.NewBatchingMLRepository(client)
+
+	result, err := batching.ProcessStreamFrame(context.Background(), 7, []byte("frame"), nil)
+	if err != nil {
+		t.Fatalf("ProcessStreamFrame: %v", err)
+	}
+	if len(result.Predictions) != 1 {
+		t.Errorf("result = %+v, want one prediction", result)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Path != "/predict" {
+		t.Errorf("requests = %+v, want one to /predict", requests)
+	}
+}
+
+func TestBatchingLeavesOneOffPredictionsAlone(t *testing.T) {
+	server, client := newClient(t, map[string]string{"ML_BATCH_WINDOW": "1h"})
+	server.SetPredictions(7, []mlfake.Prediction{{ProductID: "42"}}, nil)
+	batching := mlclient.NewBatchingMLRepository(client)
+
+	// Would block for the whole window if it joined a stream batch.
+	if _, err := batching.PredictProduct(context.Background(), 7, []byte("photo"), nil); err != nil {
+		t.Fatalf("PredictProduct: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Path != "/predict" {
+		t.Errorf("requests = %+v, want one to /predict", requests)
+	}
+}
+
+func TestBatchingIsOffByDefault(t *testing.T) {
+	_, client := newClient(t, nil)
+	if batching := mlclient.NewBatchingMLRepository(client); batching != client {
+		t.Error("NewBatchingMLRepository wrapped the client without ML_BATCH_WINDOW")
+	}
+}
@@ -0,0 +1,88 @@
+package mlclient
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops calls to the ML service after threshold consecutive
+// failures. Once cooldown has passed a single probe call is let through;
+// its success closes the breaker and its failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may go ahead. Every allowed call must be
+// followed by success, failure or abandon.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+	b.probing = false
+}
+
+// abandon releases a call that ended without telling anything about the
+// service, such as one cancelled by its caller.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
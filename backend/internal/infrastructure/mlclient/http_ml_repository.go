@@ -2,18 +2,38 @@ package mlclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"live-shopping-ai/backend/internal/domain/entities"
 	"live-shopping-ai/backend/internal/domain/repositories"
+	"math/rand"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+	// maxErrorBodySize caps how much of an error response is quoted.
+	maxErrorBodySize = 1 << 10
 )
 
 type httpMLRepository struct {
-	baseURL string
+	baseURL    string
+	client     *http.Client
+	breaker    *circuitBreaker
+	maxRetries int
+
+	predictTimeout time.Duration
+	trainTimeout   time.Duration
+	statusTimeout  time.Duration
 }
 
 func NewHttpMLRepository() repositories.MLRepository {
@@ -21,35 +41,67 @@ func NewHttpMLRepository() repositories.MLRepository {
 	if baseURL == "" {
 		baseURL = "http://ml_service:8001"
 	}
-	return &httpMLRepository{baseURL: baseURL}
-}
 
-func (r *httpMLRepository) TrainModel(sellerID string) (*entities.TrainingResponse, error) {
-	url := fmt.Sprintf("%s/train?seller_id=%s", r.baseURL, sellerID)
-	
-	resp, err := http.Post(url, "application/json", nil)
-	if err != nil {
-		return nil, err
+	// Every frame of every live stream goes to the same host, so keep
+	// plenty of idle connections to it. Deadlines come from the per-call
+	// contexts rather than the client.
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ML service returned status: %d", resp.StatusCode)
+	return &httpMLRepository{
+		baseURL:        baseURL,
+		client:         &http.Client{Transport: transport},
+		breaker:        newCircuitBreaker(envInt("ML_BREAKER_THRESHOLD", 5), envDuration("ML_BREAKER_COOLDOWN", 30*time.Second)),
+		maxRetries:     envInt("ML_MAX_RETRIES", 2),
+		predictTimeout: envDuration("ML_PREDICT_TIMEOUT", 10*time.Second),
+		trainTimeout:   envDuration("ML_TRAIN_TIMEOUT", 10*time.Minute),
+		statusTimeout:  envDuration("ML_STATUS_TIMEOUT", 5*time.Second),
 	}
+}
 
+// mlCall is one operation against the ML service. The body is kept as bytes
+// so it can be sent again on retry.
+type mlCall struct {
+	method      string
+	path        string
+	body        []byte
+	contentType string
+	timeout     time.Duration
+	// idempotent calls are retried when the service is unreachable.
+	idempotent bool
+}
+
+func (r *httpMLRepository) TrainModel(ctx context.Context, sellerID int, fineTune bool) (*entities.TrainingResponse, error) {
 	var result entities.TrainingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	err := r.do(ctx, mlCall{
+		method:      http.MethodPost,
+		path:        fmt.Sprintf("/train?seller_id=%d&fine_tune=%t", sellerID, fineTune),
+		contentType: "application/json",
+		timeout:     r.trainTimeout,
+	}, &result)
+	if err != nil {
 		return nil, err
 	}
-
 	return &result, nil
 }
 
-func (r *httpMLRepository) PredictProduct(sellerID string, imageData []byte) (*entities.PredictionResponse, error) {
+func (r *httpMLRepository) PredictProduct(ctx context.Context, sellerID int, imageData []byte, settings *entities.DetectionSettings) (*entities.PredictionResponse, error) {
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
-	writer.WriteField("seller_id", sellerID)
+	writer.WriteField("seller_id", strconv.Itoa(sellerID))
+	if err := writeDetectionSettings(writer, settings); err != nil {
+		return nil, err
+	}
 	part, err := writer.CreateFormFile("file", "image.jpg")
 	if err != nil {
 		return nil, err
@@ -57,54 +109,238 @@ func (r *httpMLRepository) PredictProduct(sellerID string, imageData []byte) (*e
 	part.Write(imageData)
 	writer.Close()
 
-	url := fmt.Sprintf("%s/predict", r.baseURL)
-	req, err := http.NewRequest("POST", url, &buf)
+	// Prediction only reads the index, so it is safe to retry.
+	var result predictionResult
+	err = r.do(ctx, mlCall{
+		method:      http.MethodPost,
+		path:        "/predict",
+		body:        buf.Bytes(),
+		contentType: writer.FormDataContentType(),
+		timeout:     r.predictTimeout,
+		idempotent:  true,
+	}, &result)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if result.Error != "" {
+		return nil, predictError(result.Error)
+	}
+	return &result.PredictionResponse, nil
+}
+
+// predictionResult is the body of a /predict response. The ML service
+// reports failed predictions with a 200 carrying an error field.
+type predictionResult struct {
+	entities.PredictionResponse
+	Error string `json:"error"`
+}
+
+func predictError(message string) error {
+	return fmt.Errorf("ML service failed to predict: %s", message)
+}
+
+// detectionOptions is the settings field of prediction requests.
+type detectionOptions struct {
+	SimilarityThreshold float64                    `json:"similarity_threshold"`
+	MinConfidence       float64                    `json:"min_confidence"`
+	IgnoredClasses      []string                   `json:"ignored_classes"`
+	ROI                 *entities.RegionOfInterest `json:"roi,omitempty"`
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// writeDetectionSettings adds the seller's settings to a prediction form.
+// Without them the ML service uses its defaults.
+func writeDetectionSettings(writer *multipart.Writer, settings *entities.DetectionSettings) error {
+	if settings == nil {
+		return nil
+	}
+	ignoredClasses := settings.IgnoredClasses
+	if ignoredClasses == nil {
+		ignoredClasses = []string{}
+	}
+	options, err := json.Marshal(detectionOptions{
+		SimilarityThreshold: settings.SimilarityThreshold,
+		MinConfidence:       settings.MinConfidence,
+		IgnoredClasses:      ignoredClasses,
+		ROI:                 settings.ROI,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
+	return writer.WriteField("settings", string(options))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ML service returned status: %d, body: %s", resp.StatusCode, string(body))
+func (r *httpMLRepository) PredictBatch(ctx context.Context, sellerID int, frames [][]byte, settings *entities.DetectionSettings) ([]entities.FramePrediction, error) {
+	if len(frames) == 0 {
+		return []entities.FramePrediction{}, nil
 	}
 
-	var result entities.PredictionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	writer.WriteField("seller_id", strconv.Itoa(sellerID))
+	if err := writeDetectionSettings(writer, settings); err != nil {
 		return nil, err
 	}
+	for i, frame := range frames {
+		part, err := writer.CreateFormFile("files", fmt.Sprintf("frame_%d.jpg", i))
+		if err != nil {
+			return nil, err
+		}
+		part.Write(frame)
+	}
+	writer.Close()
 
-	return &result, nil
+	// The service works through the frames one after another, so the batch
+	// gets as long as the frames would have separately.
+	var result entities.BatchPredictionResponse
+	err := r.do(ctx, mlCall{
+		method:      http.MethodPost,
+		path:        "/predict-batch",
+		body:        buf.Bytes(),
+		contentType: writer.FormDataContentType(),
+		timeout:     r.predictTimeout * time.Duration(len(frames)),
+		idempotent:  true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) != len(frames) {
+		return nil, fmt.Errorf("ML service returned %d results for %d frames", len(result.Results), len(frames))
+	}
+
+	for i := range result.Results {
+		frame := &result.Results[i]
+		if frame.Error != "" {
+			frame.PredictionResponse = nil
+		} else if frame.PredictionResponse == nil {
+			frame.PredictionResponse = &entities.PredictionResponse{}
+		}
+	}
+	return result.Results, nil
 }
 
-func (r *httpMLRepository) GetTrainingStatus(sellerID string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("%s/training-status/%s", r.baseURL, sellerID)
-	
-	resp, err := http.Get(url)
+func (r *httpMLRepository) GetTrainingStatus(ctx context.Context, sellerID int) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := r.do(ctx, mlCall{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/training-status/%d", sellerID),
+		timeout:    r.statusTimeout,
+		idempotent: true,
+	}, &result)
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
+
+func (r *httpMLRepository) ProcessStreamFrame(ctx context.Context, sellerID int, imageData []byte, settings *entities.DetectionSettings) (*entities.PredictionResponse, error) {
+	return r.PredictProduct(ctx, sellerID, imageData, settings)
+}
+
+// do runs a call under its timeout, retrying idempotent calls with jittered
+// backoff while the service is unreachable. Errors meaning the service is
+// down wrap entities.ErrMLUnavailable.
+func (r *httpMLRepository) do(ctx context.Context, call mlCall, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, call.timeout)
+	defer cancel()
+
+	attempts := 1
+	if call.idempotent {
+		attempts += r.maxRetries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(retryDelay(attempt)):
+			}
+		}
+
+		if !r.breaker.allow() {
+			// A retry that tripped the breaker reports why it failed.
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: circuit breaker is open", entities.ErrMLUnavailable)
+		}
+
+		err = r.attempt(ctx, call, out)
+		switch {
+		case err == nil:
+			r.breaker.success()
+			return nil
+		case errors.Is(err, entities.ErrMLUnavailable):
+			r.breaker.failure()
+		case errors.Is(err, context.Canceled):
+			r.breaker.abandon()
+			return err
+		default:
+			// The service answered, so it is up even if the call failed.
+			r.breaker.success()
+			return err
+		}
+	}
+	return err
+}
+
+func (r *httpMLRepository) attempt(ctx context.Context, call mlCall, out interface{}) error {
+	var body io.Reader
+	if call.body != nil {
+		body = bytes.NewReader(call.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, call.method, r.baseURL+call.path, body)
+	if err != nil {
+		return err
+	}
+	if call.contentType != "" {
+		req.Header.Set("Content-Type", call.contentType)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// The caller giving up says nothing about the service.
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return fmt.Errorf("%w: %s %s: %v", entities.ErrMLUnavailable, call.method, call.path, err)
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ML service returned status: %d", resp.StatusCode)
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return fmt.Errorf("%w: ML service returned status: %d", entities.ErrMLUnavailable, resp.StatusCode)
+		}
+		return fmt.Errorf("ML service returned status: %d, body: %s", resp.StatusCode, string(text))
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// retryDelay is an exponential backoff with full jitter, so callers retrying
+// together do not hit the service in lockstep.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
 	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
 
-	return result, nil
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
 }
 
-func (r *httpMLRepository) ProcessStreamFrame(sellerID string, imageData []byte) (*entities.PredictionResponse, error) {
-	return r.PredictProduct(sellerID, imageData)
-}
\ No newline at end of file
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
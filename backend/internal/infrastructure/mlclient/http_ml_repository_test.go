@@ -0,0 +1,360 @@
+package mlclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+	"live-shopping-ai/backend/internal/infrastructure/mlclient"
+	"live-shopping-ai/backend/internal/infrastructure/mlclient/mlfake"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newClient starts a fake ML service and points a client at it. Retries
+// and the breaker are configured through the environment like in
+// production.
+func newClient(t *testing.T, env map[string]string) (*mlfake.Server, repositories.MLRepository) {
+	t.Helper()
+
+	server := mlfake.New()
+	t.Cleanup(server.Close)
+
+	t.Setenv("ML_SERVICE_URL", server.URL)
+	t.Setenv("ML_MAX_RETRIES", "0")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return server, mlclient.NewHttpMLRepository()
+}
+
+func TestPredictProductSendsFrameAndDecodesPredictions(t *testing.T) {
+	server, client := newClient(t, nil)
+	server.SetPredictions(7,
+		[]mlfake.Prediction{{
+			BBox:            [4]int{10, 20, 110, 220},
+			ProductID:       "42",
+			ProductName:     "Red Mug",
+			Price:           12.5,
+			Confidence:      0.91,
+			SimilarityScore: 0.87,
+		}},
+		[]mlfake.Detection{{BBox: [4]int{10, 20, 110, 220}, Confidence: 0.91, Class: "cup", ClassID: 41}},
+	)
+
+	result, err := client.PredictProduct(context.Background(), 7, []byte("jpeg bytes"), nil)
+	if err != nil {
+		t.Fatalf("PredictProduct: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	request := requests[0]
+	if request.Method != "POST" || request.Path != "/predict" {
+		t.Errorf("request = %s %s, want POST /predict", request.Method, request.Path)
+	}
+	if request.Form["seller_id"] != "7" {
+		t.Errorf("seller_id field = %q, want %q", request.Form["seller_id"], "7")
+	}
+	if string(request.File) != "jpeg bytes" {
+		t.Errorf("file = %q, want the frame", request.File)
+	}
+
+	if len(result.Predictions) != 1 {
+		t.Fatalf("got %d predictions, want 1", len(result.Predictions))
+	}
+	prediction := result.Predictions[0]
+	if prediction.ProductID != "42" || prediction.ProductName != "Red Mug" || prediction.Price != 12.5 {
+		t.Errorf("prediction = %+v", prediction)
+	}
+	if prediction.Confidence != 0.91 || prediction.SimilarityScore != 0.87 {
+		t.Errorf("prediction scores = %v, %v", prediction.Confidence, prediction.SimilarityScore)
+	}
+	if want := []int{10, 20, 110, 220}; !equalInts(prediction.BBox, want) {
+		t.Errorf("prediction bbox = %v, want %v", prediction.BBox, want)
+	}
+
+	if len(result.Detections) != 1 {
+		t.Fatalf("got %d detections, want 1", len(result.Detections))
+	}
+	detection := result.Detections[0]
+	if detection.Class != "cup" || detection.ClassID != 41 || detection.Confidence != 0.91 {
+		t.Errorf("detection = %+v", detection)
+	}
+	if result.TotalDetections != 1 || result.TotalProducts != 1 {
+		t.Errorf("totals = %d detections, %d products, want 1 and 1", result.TotalDetections, result.TotalProducts)
+	}
+}
+
+func TestPredictProductForUntrainedSellerFindsNothing(t *testing.T) {
+	_, client := newClient(t, nil)
+
+	result, err := client.PredictProduct(context.Background(), 3, []byte("frame"), nil)
+	if err != nil {
+		t.Fatalf("PredictProduct: %v", err)
+	}
+	if len(result.Predictions) != 0 || len(result.Detections) != 0 {
+		t.Errorf("result = %+v, want no predictions or detections", result)
+	}
+}
+
+func TestPredictProductReportsErrorField(t *testing.T) {
+	server, client := newClient(t, nil)
+	server.SetPredictions(7, []mlfake.Prediction{{ProductID: "42"}}, nil)
+	server.SetMode(mlfake.ModeFailing)
+
+	result, err := client.PredictProduct(context.Background(), 7, []byte("frame"), nil)
+	if err == nil {
+		t.Fatalf("PredictProduct returned %+v, want an error", result)
+	}
+	if !strings.Contains(err.Error(), mlfake.FailureMessage) {
+		t.Errorf("error %q does not carry the service's message", err)
+	}
+	// The service answered, so it is not down.
+	if errors.Is(err, entities.ErrMLUnavailable) {
+		t.Errorf("error %q should not mean the service is unavailable", err)
+	}
+}
+
+func TestPredictProductForwardsDetectionSettings(t *testing.T) {
+	server, client := newClient(t, nil)
+	server.SetPredictions(7,
+		[]mlfake.Prediction{
+			{ProductID: "42", Confidence: 0.9, SimilarityScore: 0.9},
+			{ProductID: "43", Confidence: 0.9, SimilarityScore: 0.75},
+		},
+		[]mlfake.Detection{
+			{Class: "cup", Confidence: 0.9},
+			{Class: "person", Confidence: 0.95},
+			{Class: "bottle", Confidence: 0.3},
+		},
+	)
+	settings := &entities.DetectionSettings{
+		SellerID:            7,
+		SimilarityThreshold: 0.8,
+		MinConfidence:       0.5,
+		IgnoredClasses:      []string{"person"},
+		ROI:                 &entities.RegionOfInterest{X: 0.1, Y: 0.2, Width: 0.5, Height: 0.6},
+	}
+
+	result, err := client.PredictProduct(context.Background(), 7, []byte("frame"), settings)
+	if err != nil {
+		t.Fatalf("PredictProduct: %v", err)
+	}
+
+	var sent mlfake.Settings
+	if err := json.Unmarshal([]byte(server.Requests()[0].Form["settings"]), &sent); err != nil {
+		t.Fatalf("settings field %q: %v", server.Requests()[0].Form["settings"], err)
+	}
+	if sent.SimilarityThreshold != 0.8 || sent.MinConfidence != 0.5 || len(sent.IgnoredClasses) != 1 || sent.IgnoredClasses[0] != "person" {
+		t.Errorf("sent settings = %+v", sent)
+	}
+	if sent.ROI == nil || sent.ROI.X != 0.1 || sent.ROI.Y != 0.2 || sent.ROI.Width != 0.5 || sent.ROI.Height != 0.6 {
+		t.Errorf("sent roi = %+v", sent.ROI)
+	}
+
+	if len(result.Predictions) != 1 || result.Predictions[0].ProductID != "42" {
+		t.Errorf("predictions = %+v, want only the one above the threshold", result.Predictions)
+	}
+	if len(result.Detections) != 1 || result.Detections[0].Class != "cup" {
+		t.Errorf("detections = %+v, want only the cup", result.Detections)
+	}
+
+	// Without settings the service falls back to its own defaults.
+	if _, err := client.PredictProduct(context.Background(), 7, []byte("frame"), nil); err != nil {
+		t.Fatalf("PredictProduct: %v", err)
+	}
+	if _, ok := server.Requests()[1].Form["settings"]; ok {
+		t.Error("settings field sent without settings")
+	}
+}
+
+func TestPredictBatchReturnsResultsInFrameOrder(t *testing.T) {
+	server, client := newClient(t, nil)
+	server.SetPredictions(7, []mlfake.Prediction{{ProductID: "42", Confidence: 0.9}}, nil)
+
+	frames := [][]byte{[]byte("first"), {}, []byte("third")}
+	results, err := client.PredictBatch(context.Background(), 7, frames, nil)
+	if err != nil {
+		t.Fatalf("PredictBatch: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	request := requests[0]
+	if request.Method != "POST" || request.Path != "/predict-batch" {
+		t.Errorf("request = %s %s, want POST /predict-batch", request.Method, request.Path)
+	}
+	if request.Form["seller_id"] != "7" {
+		t.Errorf("seller_id field = %q, want %q", request.Form["seller_id"], "7")
+	}
+	if len(request.Files) != len(frames) {
+		t.Fatalf("sent %d files, want %d", len(request.Files), len(frames))
+	}
+	for i := range frames {
+		if string(request.Files[i]) != string(frames[i]) {
+			t.Errorf("file %d = %q, want %q", i, request.Files[i], frames[i])
+		}
+	}
+
+	if len(results) != len(frames) {
+		t.Fatalf("got %d results, want %d", len(results), len(frames))
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Error != "" || results[i].PredictionResponse == nil {
+			t.Fatalf("result %d = %+v, want a prediction", i, results[i])
+		}
+		if len(results[i].Predictions) != 1 || results[i].Predictions[0].ProductID != "42" {
+			t.Errorf("result %d predictions = %+v", i, results[i].Predictions)
+		}
+	}
+	// The unreadable frame fails on its own.
+	if results[1].Error != mlfake.UnreadableFrameMessage || results[1].PredictionResponse != nil {
+		t.Errorf("result 1 = %+v, want only the frame's error", results[1])
+	}
+}
+
+func TestTrainModelStartsTraining(t *testing.T) {
+	server, client := newClient(t, nil)
+
+	result, err := client.TrainModel(context.Background(), 5, true)
+	if err != nil {
+		t.Fatalf("TrainModel: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if requests[0].Method != "POST" || requests[0].Path != "/train" {
+		t.Errorf("request = %s %s, want POST /train", requests[0].Method, requests[0].Path)
+	}
+	if requests[0].Query != "seller_id=5&fine_tune=true" {
+		t.Errorf("query = %q", requests[0].Query)
+	}
+
+	if result.Status != "training_started" || result.SellerID != "5" || !result.FineTune {
+		t.Errorf("result = %+v", result)
+	}
+
+	status, err := client.GetTrainingStatus(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetTrainingStatus: %v", err)
+	}
+	if status["status"] != "training" {
+		t.Errorf("status after starting = %v, want training", status)
+	}
+}
+
+func TestTrainModelReportsFailure(t *testing.T) {
+	server, client := newClient(t, map[string]string{"ML_MAX_RETRIES": "2"})
+	server.SetMode(mlfake.ModeFailing)
+
+	_, err := client.TrainModel(context.Background(), 5, false)
+	if err == nil {
+		t.Fatal("TrainModel succeeded, want an error")
+	}
+	if !strings.Contains(err.Error(), "500") || !strings.Contains(err.Error(), mlfake.FailureMessage) {
+		t.Errorf("error %q should carry the status and detail", err)
+	}
+	if errors.Is(err, entities.ErrMLUnavailable) {
+		t.Errorf("error %q should not mean the service is unavailable", err)
+	}
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("training was sent %d times, want once", got)
+	}
+}
+
+func TestGetTrainingStatus(t *testing.T) {
+	server, client := newClient(t, nil)
+
+	status, err := client.GetTrainingStatus(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("GetTrainingStatus: %v", err)
+	}
+	if status["status"] != "not_started" {
+		t.Errorf("status of an untrained seller = %v, want not_started", status)
+	}
+
+	server.SetTrainingStatus(9, map[string]interface{}{"status": "training", "progress": 50, "message": "Training model..."})
+	status, err = client.GetTrainingStatus(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("GetTrainingStatus: %v", err)
+	}
+	if status["status"] != "training" || status["progress"] != float64(50) || status["message"] != "Training model..." {
+		t.Errorf("status = %v", status)
+	}
+
+	requests := server.Requests()
+	if path := requests[len(requests)-1].Path; path != "/training-status/9" {
+		t.Errorf("path = %q, want /training-status/9", path)
+	}
+}
+
+func TestUnavailableServiceIsRetried(t *testing.T) {
+	server, client := newClient(t, map[string]string{"ML_MAX_RETRIES": "2"})
+	server.SetMode(mlfake.ModeUnavailable)
+
+	_, err := client.GetTrainingStatus(context.Background(), 1)
+	if !errors.Is(err, entities.ErrMLUnavailable) {
+		t.Fatalf("error = %v, want ErrMLUnavailable", err)
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestSlowResponseTimesOut(t *testing.T) {
+	server, client := newClient(t, map[string]string{"ML_PREDICT_TIMEOUT": "100ms"})
+	server.SetPredictions(7, nil, nil)
+	server.SetDelay(2 * time.Second)
+
+	start := time.Now()
+	_, err := client.PredictProduct(context.Background(), 7, []byte("frame"), nil)
+	if !errors.Is(err, entities.ErrMLUnavailable) {
+		t.Fatalf("error = %v, want ErrMLUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("PredictProduct took %s, want it cut off at the timeout", elapsed)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	server, client := newClient(t, map[string]string{
+		"ML_BREAKER_THRESHOLD": "2",
+		"ML_BREAKER_COOLDOWN":  "1h",
+	})
+	server.SetMode(mlfake.ModeUnavailable)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTrainingStatus(context.Background(), 1); !errors.Is(err, entities.ErrMLUnavailable) {
+			t.Fatalf("call %d: error = %v, want ErrMLUnavailable", i+1, err)
+		}
+	}
+
+	server.SetMode(mlfake.ModeOK)
+	if _, err := client.GetTrainingStatus(context.Background(), 1); !errors.Is(err, entities.ErrMLUnavailable) {
+		t.Fatalf("error = %v, want the open breaker to fail fast", err)
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
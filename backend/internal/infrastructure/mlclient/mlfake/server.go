@@ -0,0 +1,406 @@
+// Package mlfake is an in-process stand-in for the Python ML service. It
+// answers /train, /predict, /predict-batch and /training-status with the
+// same JSON shapes
+// the real service does, including the ones it uses for failures, so the ML
+// client can be tested against the wire format without the models.
+package mlfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode selects how the server fails.
+type Mode int
+
+const (
+	// ModeOK answers normally.
+	ModeOK Mode = iota
+	// ModeFailing fails the way the Python handlers do when they raise:
+	// /predict still answers 200 but with an error field and no
+	// detections, /train answers 500 with a detail.
+	ModeFailing
+	// ModeUnavailable answers 503 to everything, like a proxy in front of
+	// a service that is down.
+	ModeUnavailable
+)
+
+// Prediction is one recognised product as /predict reports it.
+type Prediction struct {
+	BBox            [4]int  `json:"bbox"`
+	ProductID       string  `json:"product_id"`
+	ProductName     string  `json:"product_name"`
+	Price           float64 `json:"price"`
+	Confidence      float64 `json:"confidence"`
+	SimilarityScore float64 `json:"similarity_score"`
+}
+
+// Detection is one YOLO detection as /predict reports it.
+type Detection struct {
+	BBox       [4]int  `json:"bbox"`
+	Confidence float64 `json:"confidence"`
+	Class      string  `json:"class"`
+	ClassID    int     `json:"class_id"`
+}
+
+// Settings is the settings field of /predict and /predict-batch requests.
+type Settings struct {
+	SimilarityThreshold float64  `json:"similarity_threshold"`
+	MinConfidence       float64  `json:"min_confidence"`
+	IgnoredClasses      []string `json:"ignored_classes"`
+	ROI                 *struct {
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	} `json:"roi"`
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	// Form holds the multipart fields of /predict and /predict-batch
+	// requests, settings included.
+	Form map[string]string
+	// File is the uploaded image of /predict requests.
+	File []byte
+	// Files are the uploaded images of /predict-batch requests, in order.
+	Files [][]byte
+}
+
+// FailureMessage is the error the server reports in ModeFailing.
+const FailureMessage = "fake ML service failure"
+
+// UnreadableFrameMessage is the error reported for an empty frame, which
+// the real service cannot decode as an image.
+const UnreadableFrameMessage = "cannot identify image file"
+
+type sellerState struct {
+	trained     bool
+	predictions []Prediction
+	detections  []Detection
+	status      map[string]interface{}
+}
+
+// Server is a fake ML service listening on a local port.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	mode     Mode
+	delay    time.Duration
+	sellers  map[string]*sellerState
+	requests []Request
+}
+
+// New starts a server. Close it when done.
+func New() *Server {
+	s := &Server{sellers: make(map[string]*sellerState)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/train", s.handleTrain)
+	mux.HandleFunc("/predict", s.handlePredict)
+	mux.HandleFunc("/predict-batch", s.handlePredictBatch)
+	mux.HandleFunc("/training-status/", s.handleTrainingStatus)
+	s.Server = httptest.NewServer(s.intercept(mux))
+	return s
+}
+
+// SetMode switches how the server fails from now on.
+func (s *Server) SetMode(mode Mode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+}
+
+// SetDelay makes every response wait d first, or until the client gives
+// up.
+func (s *Server) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// SetPredictions marks the seller as trained and sets what /predict finds
+// in their frames.
+func (s *Server) SetPredictions(sellerID int, predictions []Prediction, detections []Detection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seller := s.seller(sellerKey(sellerID))
+	seller.trained = true
+	seller.predictions = predictions
+	seller.detections = detections
+}
+
+// SetTrainingStatus sets what /training-status reports for the seller.
+func (s *Server) SetTrainingStatus(sellerID int, status map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seller(sellerKey(sellerID)).status = status
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func sellerKey(sellerID int) string {
+	return fmt.Sprintf("seller_%d", sellerID)
+}
+
+func (s *Server) seller(key string) *sellerState {
+	seller, ok := s.sellers[key]
+	if !ok {
+		seller = &sellerState{}
+		s.sellers[key] = seller
+	}
+	return seller
+}
+
+// intercept records each request and applies the delay and the
+// unavailable mode before the endpoint sees it.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			if err := r.ParseMultipartForm(32 << 20); err == nil {
+				request.Form = make(map[string]string)
+				for name, values := range r.MultipartForm.Value {
+					request.Form[name] = values[0]
+				}
+				if files := formFiles(r, "file"); len(files) > 0 {
+					request.File = files[0]
+				}
+				request.Files = formFiles(r, "files")
+			}
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		mode, delay := s.mode, s.delay
+		s.mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if mode == ModeUnavailable {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"detail": "Service Unavailable"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleTrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"detail": "Method Not Allowed"})
+		return
+	}
+	sellerID := r.URL.Query().Get("seller_id")
+	if sellerID == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"detail": "seller_id is required"})
+		return
+	}
+	fineTune := r.URL.Query().Get("fine_tune") == "true"
+	key := "seller_" + sellerID
+
+	s.mu.Lock()
+	mode := s.mode
+	if mode == ModeFailing {
+		s.seller(key).status = map[string]interface{}{"status": "error", "message": FailureMessage}
+	} else {
+		s.seller(key).status = map[string]interface{}{"status": "training", "progress": 0, "message": "Starting training..."}
+	}
+	s.mu.Unlock()
+
+	if mode == ModeFailing {
+		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"detail": FailureMessage})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "training_started",
+		"seller_id":  sellerID,
+		"seller_key": key,
+		"fine_tune":  fineTune,
+	})
+}
+
+func (s *Server) handlePredict(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"detail": "Method Not Allowed"})
+		return
+	}
+	// The form was parsed when the request was recorded.
+	sellerID := r.FormValue("seller_id")
+	if sellerID == "" || r.MultipartForm == nil || len(r.MultipartForm.File["file"]) == 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"detail": "seller_id and file are required"})
+		return
+	}
+
+	settings, err := parseSettings(r)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"detail": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.predict(sellerID, settings, formFiles(r, "file")[0]))
+}
+
+func (s *Server) handlePredictBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"detail": "Method Not Allowed"})
+		return
+	}
+	sellerID := r.FormValue("seller_id")
+	if sellerID == "" || r.MultipartForm == nil || len(r.MultipartForm.File["files"]) == 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"detail": "seller_id and files are required"})
+		return
+	}
+	settings, err := parseSettings(r)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"detail": err.Error()})
+		return
+	}
+
+	frames := formFiles(r, "files")
+	results := make([]map[string]interface{}, 0, len(frames))
+	for _, frame := range frames {
+		results = append(results, s.predict(sellerID, settings, frame))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+// parseSettings reads the optional settings field of a prediction request.
+func parseSettings(r *http.Request) (*Settings, error) {
+	value := r.FormValue("settings")
+	if value == "" {
+		return nil, nil
+	}
+	var settings Settings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, fmt.Errorf("invalid settings: %v", err)
+	}
+	return &settings, nil
+}
+
+// predict answers for one frame the way /predict does. Without settings
+// the configured predictions and detections are returned as they are;
+// with them, the ones the settings rule out are left out. The fake does not
+// decode frames, so it cannot apply a region of interest.
+func (s *Server) predict(sellerID string, settings *Settings, frame []byte) map[string]interface{} {
+	s.mu.Lock()
+	mode := s.mode
+	seller := s.seller("seller_" + sellerID)
+	trained := seller.trained
+	predictions := append([]Prediction{}, seller.predictions...)
+	detections := append([]Detection{}, seller.detections...)
+	s.mu.Unlock()
+
+	if settings != nil {
+		predictions, detections = settings.filter(predictions, detections)
+	}
+
+	switch {
+	case mode == ModeFailing:
+		return map[string]interface{}{
+			"predictions":      []Prediction{},
+			"total_detections": 0,
+			"error":            FailureMessage,
+		}
+	case !trained:
+		return map[string]interface{}{
+			"predictions":      []Prediction{},
+			"total_detections": 0,
+			"message":          fmt.Sprintf("No trained model found for seller %s. Please train first.", sellerID),
+		}
+	case len(frame) == 0:
+		return map[string]interface{}{
+			"predictions":      []Prediction{},
+			"total_detections": 0,
+			"error":            UnreadableFrameMessage,
+		}
+	default:
+		return map[string]interface{}{
+			"predictions":      predictions,
+			"detections":       detections,
+			"total_detections": len(detections),
+			"total_products":   len(predictions),
+		}
+	}
+}
+
+func (s *Server) handleTrainingStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"detail": "Method Not Allowed"})
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/training-status/")
+	if !strings.HasPrefix(key, "seller_") {
+		key = "seller_" + key
+	}
+
+	s.mu.Lock()
+	status := map[string]interface{}{"status": "not_started"}
+	if seller, ok := s.sellers[key]; ok && seller.status != nil {
+		status = seller.status
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (settings *Settings) filter(predictions []Prediction, detections []Detection) ([]Prediction, []Detection) {
+	ignored := make(map[string]bool)
+	for _, class := range settings.IgnoredClasses {
+		ignored[class] = true
+	}
+
+	keptDetections := []Detection{}
+	for _, detection := range detections {
+		if detection.Confidence > settings.MinConfidence && !ignored[detection.Class] {
+			keptDetections = append(keptDetections, detection)
+		}
+	}
+	keptPredictions := []Prediction{}
+	for _, prediction := range predictions {
+		if prediction.Confidence > settings.MinConfidence && prediction.SimilarityScore > settings.SimilarityThreshold {
+			keptPredictions = append(keptPredictions, prediction)
+		}
+	}
+	return keptPredictions, keptDetections
+}
+
+// formFiles reads the files uploaded under name in a parsed multipart form.
+func formFiles(r *http.Request, name string) [][]byte {
+	var files [][]byte
+	for _, header := range r.MultipartForm.File[name] {
+		file, err := header.Open()
+		if err != nil {
+			continue
+		}
+		data, _ := io.ReadAll(file)
+		file.Close()
+		files = append(files, data)
+	}
+	return files
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
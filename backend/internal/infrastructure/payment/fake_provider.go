@@ -0,0 +1,293 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/repositories"
+)
+
+const (
+	FakeProviderName = "fake"
+
+	// FakeSignatureHeader carries "t=<unix seconds>,v1=<hex hmac>" where the
+	// HMAC-SHA256 is computed over "<t>.<raw body>".
+	FakeSignatureHeader = "Fake-Signature"
+
+	fakeSignatureTolerance = 5 * time.Minute
+)
+
+// Outcomes the fake gateway simulates after an intent is created, chosen
+// with FAKE_PAYMENT_OUTCOME.
+const (
+	fakeOutcomeAuthorize = "authorize"
+	fakeOutcomeFail      = "fail"
+	fakeOutcomeNone      = "none"
+)
+
+type fakeWebhookBody struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentID string `json:"payment_id"`
+	} `json:"data"`
+}
+
+type fakeIntent struct {
+	amount float64
+	status string
+}
+
+// fakeProvider is a local payment gateway for development and tests. It keeps
+// intents in memory and, unless FAKE_PAYMENT_OUTCOME is "none", posts a
+// signed webhook back to the backend shortly after each intent is created,
+// exercising the same path a real provider would.
+type fakeProvider struct {
+	secret     []byte
+	webhookURL string
+	outcome    string
+	delay      time.Duration
+	client     *http.Client
+
+	mu      sync.Mutex
+	intents map[string]*fakeIntent
+}
+
+func NewFakePaymentProvider() repositories.PaymentProvider {
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		secret = randomID("")
+		log.Println("PAYMENT_WEBHOOK_SECRET is not set, using a random secret for the fake payment provider")
+	}
+
+	webhookURL := os.Getenv("FAKE_PAYMENT_WEBHOOK_URL")
+	if webhookURL == "" {
+		webhookURL = "http://localhost:8080/api/payments/webhook/" + FakeProviderName
+	}
+
+	outcome := os.Getenv("FAKE_PAYMENT_OUTCOME")
+	if outcome == "" {
+		outcome = fakeOutcomeAuthorize
+	}
+
+	delay, err := time.ParseDuration(os.Getenv("FAKE_PAYMENT_DELAY"))
+	if err != nil {
+		delay = 2 * time.Second
+	}
+
+	return &fakeProvider{
+		secret:     []byte(secret),
+		webhookURL: webhookURL,
+		outcome:    outcome,
+		delay:      delay,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		intents:    make(map[string]*fakeIntent),
+	}
+}
+
+func (p *fakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *fakeProvider) CreateIntent(ctx context.Context, params *entities.PaymentIntentParams) (*entities.PaymentIntent, error) {
+	if params.Amount <= 0 {
+		return nil, fmt.Errorf("%w: payment amount must be positive", entities.ErrInvalidInput)
+	}
+
+	id := randomID("fake_pi_")
+	p.mu.Lock()
+	p.intents[id] = &fakeIntent{amount: params.Amount, status: entities.PaymentStatusPending}
+	p.mu.Unlock()
+
+	switch p.outcome {
+	case fakeOutcomeAuthorize:
+		go p.sendWebhookAfterDelay(entities.PaymentEventAuthorized, id)
+	case fakeOutcomeFail:
+		go p.sendWebhookAfterDelay(entities.PaymentEventFailed, id)
+	}
+
+	return &entities.PaymentIntent{
+		ProviderPaymentID: id,
+		Status:            entities.PaymentStatusPending,
+		ClientSecret:      id + "_secret_" + randomID(""),
+	}, nil
+}
+
+func (p *fakeProvider) Capture(ctx context.Context, providerPaymentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerPaymentID]
+	if !ok {
+		return fmt.Errorf("fake payment %s: %w", providerPaymentID, entities.ErrNotFound)
+	}
+	if intent.status == entities.PaymentStatusSucceeded {
+		return nil
+	}
+	if intent.status != entities.PaymentStatusAuthorized {
+		return fmt.Errorf("%w: fake payment %s is %s, not authorized", entities.ErrConflict, providerPaymentID, intent.status)
+	}
+	intent.status = entities.PaymentStatusSucceeded
+	return nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, providerPaymentID string, amount float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerPaymentID]
+	if !ok {
+		return fmt.Errorf("fake payment %s: %w", providerPaymentID, entities.ErrNotFound)
+	}
+	if intent.status != entities.PaymentStatusSucceeded && intent.status != entities.PaymentStatusRefunded {
+		return fmt.Errorf("%w: fake payment %s is %s, not captured", entities.ErrConflict, providerPaymentID, intent.status)
+	}
+	if amount > intent.amount {
+		return fmt.Errorf("%w: refund exceeds captured amount", entities.ErrInvalidInput)
+	}
+	intent.status = entities.PaymentStatusRefunded
+	return nil
+}
+
+func (p *fakeProvider) VerifyWebhook(payload []byte, header http.Header) (*entities.PaymentWebhookEvent, error) {
+	timestamp, signature, ok := parseFakeSignature(header.Get(FakeSignatureHeader))
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed %s header", entities.ErrInvalidSignature, FakeSignatureHeader)
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > fakeSignatureTolerance || age < -fakeSignatureTolerance {
+		return nil, fmt.Errorf("%w: timestamp outside tolerance", entities.ErrInvalidSignature)
+	}
+
+	expected := SignFakeWebhook(p.secret, timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, entities.ErrInvalidSignature
+	}
+
+	var body fakeWebhookBody
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidInput, err)
+	}
+	if body.ID == "" || body.Type == "" || body.Data.PaymentID == "" {
+		return nil, fmt.Errorf("%w: webhook is missing id, type or payment_id", entities.ErrInvalidInput)
+	}
+
+	// Mirror the notified state locally so Capture and Refund see it. The
+	// same event may be delivered again after the intent has moved on, so
+	// it only ever moves an intent forward.
+	p.mu.Lock()
+	if intent, ok := p.intents[body.Data.PaymentID]; ok {
+		open := intent.status == entities.PaymentStatusPending || intent.status == entities.PaymentStatusAuthorized
+		switch {
+		case body.Type == entities.PaymentEventAuthorized && intent.status == entities.PaymentStatusPending:
+			intent.status = entities.PaymentStatusAuthorized
+		case body.Type == entities.PaymentEventFailed && open:
+			intent.status = entities.PaymentStatusFailed
+		}
+	}
+	p.mu.Unlock()
+
+	return &entities.PaymentWebhookEvent{
+		EventID:           body.ID,
+		Type:              body.Type,
+		ProviderPaymentID: body.Data.PaymentID,
+		Payload:           payload,
+	}, nil
+}
+
+// SignFakeWebhook returns the v1 signature for a fake webhook payload. It is
+// exported so webhooks can be crafted by hand during development.
+func SignFakeWebhook(secret []byte, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseFakeSignature(header string) (int64, string, bool) {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, "", false
+			}
+			timestamp = t
+		case "v1":
+			signature = value
+		}
+	}
+	return timestamp, signature, timestamp != 0 && signature != ""
+}
+
+func (p *fakeProvider) sendWebhookAfterDelay(eventType, paymentID string) {
+	time.Sleep(p.delay)
+
+	body := fakeWebhookBody{ID: randomID("fake_evt_"), Type: eventType}
+	body.Data.PaymentID = paymentID
+	payload, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("fake payment: failed to encode webhook: %v", err)
+		return
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if err := p.postWebhook(payload); err != nil {
+			log.Printf("fake payment: webhook %s for %s failed (attempt %d): %v", eventType, paymentID, attempt, err)
+			time.Sleep(time.Duration(attempt) * time.Second)
+			continue
+		}
+		return
+	}
+}
+
+func (p *fakeProvider) postWebhook(payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, p.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakeSignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, SignFakeWebhook(p.secret, timestamp, payload)))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func randomID(prefix string) string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return prefix + hex.EncodeToString(buf)
+}
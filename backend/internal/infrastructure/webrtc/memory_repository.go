@@ -115,8 +115,8 @@ func (r *memoryWebRTCRepository) BroadcastToRoom(roomID string, message interfac
 	defer room.Mutex.RUnlock()
 
 	for _, client := range room.Clients {
-		if client.ID != excludeClientID && client.Conn != nil {
-			if err := client.Conn.WriteJSON(message); err != nil {
+		if client.ID != excludeClientID && client.Reachable() {
+			if err := client.WriteJSON(message); err != nil {
 				continue
 			}
 		}
@@ -127,9 +127,9 @@ func (r *memoryWebRTCRepository) BroadcastToRoom(roomID string, message interfac
 
 func (r *memoryWebRTCRepository) SendToClient(roomID, clientID string, message interface{}) error {
 	client := r.GetClient(roomID, clientID)
-	if client == nil || client.Conn == nil {
+	if client == nil || !client.Reachable() {
 		return nil
 	}
 
-	return client.Conn.WriteJSON(message)
+	return client.WriteJSON(message)
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"live-shopping-ai/backend/internal/domain/entities"
+	"live-shopping-ai/backend/internal/domain/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// RequireAuth rejects requests without a valid bearer access token and stores
+// the authenticated principal on the context.
+func RequireAuth(authService services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := authenticate(c, authService)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// OptionalAuth stores the principal when a valid token is present but lets
+// anonymous requests through.
+func OptionalAuth(authService services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := authenticate(c, authService); ok {
+			c.Set(principalKey, principal)
+		}
+		c.Next()
+	}
+}
+
+// RequireSeller must run after RequireAuth.
+func RequireSeller() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !GetPrincipal(c).IsSeller() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "seller account required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetPrincipal returns the authenticated caller, or nil for anonymous requests.
+func GetPrincipal(c *gin.Context) *entities.Principal {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	principal, _ := value.(*entities.Principal)
+	return principal
+}
+
+func authenticate(c *gin.Context, authService services.AuthService) (*entities.Principal, bool) {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, false
+	}
+
+	principal, err := authService.Authenticate(strings.TrimPrefix(header, "Bearer "))
+	if err != nil {
+		return nil, false
+	}
+	return principal, true
+}
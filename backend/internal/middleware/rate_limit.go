@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitIdleTTL is how long a client's bucket is kept after its last
+// request. A bucket idle this long has refilled completely anyway.
+const rateLimitIdleTTL = 10 * time.Minute
+
+type rateBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimit allows each client perMinute requests a minute, in bursts of up
+// to perMinute. Signed-in callers are counted per user and anonymous ones
+// per IP, so it must run after OptionalAuth or RequireAuth.
+func RateLimit(perMinute int) gin.HandlerFunc {
+	refill := float64(perMinute) / time.Minute.Seconds()
+	capacity := float64(perMinute)
+
+	var mu sync.Mutex
+	buckets := make(map[string]*rateBucket)
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if principal := GetPrincipal(c); principal != nil {
+			key = "user:" + strconv.Itoa(principal.UserID)
+		}
+		now := time.Now()
+
+		mu.Lock()
+		if now.Sub(lastSweep) > rateLimitIdleTTL {
+			for k, bucket := range buckets {
+				if now.Sub(bucket.lastSeen) > rateLimitIdleTTL {
+					delete(buckets, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &rateBucket{tokens: capacity, lastSeen: now}
+			buckets[key] = bucket
+		}
+		bucket.tokens += now.Sub(bucket.lastSeen).Seconds() * refill
+		if bucket.tokens > capacity {
+			bucket.tokens = capacity
+		}
+		bucket.lastSeen = now
+
+		allowed := bucket.tokens >= 1
+		var retryAfter time.Duration
+		if allowed {
+			bucket.tokens--
+		} else {
+			retryAfter = time.Duration((1 - bucket.tokens) / refill * float64(time.Second))
+		}
+		mu.Unlock()
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
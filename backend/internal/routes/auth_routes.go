@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuthRoutes(r *gin.Engine, authHandler *handlers.AuthHandler, authService services.AuthService) {
+	requireAuth := middleware.RequireAuth(authService)
+
+	api := r.Group("/api/auth")
+	{
+		api.POST("/register", authHandler.Register)
+		api.POST("/login", authHandler.Login)
+		api.POST("/refresh", authHandler.Refresh)
+		api.POST("/logout", requireAuth, authHandler.Logout)
+		api.GET("/me", requireAuth, authHandler.Me)
+		api.POST("/seller", requireAuth, authHandler.BecomeSeller)
+		api.POST("/stream-token", middleware.OptionalAuth(authService), authHandler.IssueStreamToken)
+	}
+}
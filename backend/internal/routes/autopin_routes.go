@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAutoPinRoutes(r *gin.Engine, autoPinHandler *handlers.AutoPinHandler, authService services.AuthService) {
+	seller := r.Group("/api/autopin", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.GET("/settings", autoPinHandler.GetSettings)
+		seller.PUT("/settings", autoPinHandler.UpdateSettings)
+	}
+}
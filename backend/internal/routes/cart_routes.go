@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCartRoutes(r *gin.Engine, cartHandler *handlers.CartHandler, authService services.AuthService) {
+	cart := r.Group("/api/cart", middleware.RequireAuth(authService))
+	{
+		cart.GET("", cartHandler.GetCart)
+		cart.POST("/items", cartHandler.AddItem)
+		cart.PUT("/items/:item_id", cartHandler.UpdateItem)
+		cart.DELETE("/items/:item_id", cartHandler.RemoveItem)
+		cart.POST("/checkout", cartHandler.Checkout)
+	}
+}
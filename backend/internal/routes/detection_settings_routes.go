@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDetectionSettingsRoutes(r *gin.Engine, settingsHandler *handlers.DetectionSettingsHandler, authService services.AuthService) {
+	seller := r.Group("/api/detection", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.GET("/settings", settingsHandler.GetSettings)
+		seller.PUT("/settings", settingsHandler.UpdateSettings)
+		seller.DELETE("/settings", settingsHandler.ResetSettings)
+	}
+}
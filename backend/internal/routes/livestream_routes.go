@@ -1,20 +1,28 @@
 package routes
 
 import (
+	"live-shopping-ai/backend/internal/domain/services"
 	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupLiveStreamRoutes(router *gin.Engine, handler *handlers.LiveStreamHandler) {
+func SetupLiveStreamRoutes(router *gin.Engine, handler *handlers.LiveStreamHandler, authService services.AuthService) {
 	api := router.Group("/api")
 	{
 		livestream := api.Group("/livestreams")
 		{
-			livestream.POST("/start", handler.StartLiveStream)
-			livestream.POST("/end/:seller_id", handler.EndLiveStream)
+			livestream.GET("", handler.GetLiveStreams)
 			livestream.GET("/active", handler.GetActiveLiveStreams)
 			livestream.GET("/seller/:seller_id", handler.GetLiveStreamBySellerID)
+			livestream.GET("/:id/timeline", handler.GetTimeline)
+		}
+
+		seller := api.Group("/livestreams", middleware.RequireAuth(authService), middleware.RequireSeller())
+		{
+			seller.POST("/start", handler.StartLiveStream)
+			seller.POST("/end/:seller_id", handler.EndLiveStream)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOrderRoutes(r *gin.Engine, orderHandler *handlers.OrderHandler, authService services.AuthService) {
+	buyer := r.Group("/api/orders", middleware.RequireAuth(authService))
+	{
+		buyer.GET("", orderHandler.GetOrders)
+		buyer.GET("/:id", orderHandler.GetOrder)
+		buyer.POST("/:id/cancel", orderHandler.CancelOrder)
+	}
+
+	seller := r.Group("/api", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.GET("/seller/orders", orderHandler.GetSellerOrders)
+		seller.POST("/orders/:id/transitions", orderHandler.TransitionOrder)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPaymentRoutes(r *gin.Engine, paymentHandler *handlers.PaymentHandler, authService services.AuthService) {
+	r.POST("/api/payments/webhook/:provider", paymentHandler.Webhook)
+
+	buyer := r.Group("/api/orders", middleware.RequireAuth(authService))
+	{
+		buyer.POST("/:id/pay", paymentHandler.PayOrder)
+	}
+
+	seller := r.Group("/api/orders", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.POST("/:id/refund", paymentHandler.RefundOrder)
+	}
+}
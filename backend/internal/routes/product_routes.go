@@ -1,28 +1,37 @@
 package routes
 
 import (
+	"live-shopping-ai/backend/internal/domain/services"
 	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterProductRoutes(r *gin.Engine, productHandler *handlers.ProductHandler) {
+func RegisterProductRoutes(r *gin.Engine, productHandler *handlers.ProductHandler, authService services.AuthService) {
 	api := r.Group("/api")
 	{
 		api.GET("/products", productHandler.GetProducts)
-		api.POST("/products", productHandler.CreateProduct)
+		api.GET("/products/search", productHandler.SearchProducts)
 		api.GET("/products/:id", productHandler.GetProduct)
+		api.GET("/products/:id/stock", productHandler.GetStock)
 		api.GET("/products/seller/:id", productHandler.GetProductsBySeller)
-		api.PUT("/products/:id", productHandler.UpdateProduct)
-		api.DELETE("/products/:id", productHandler.DeleteProduct)
-		api.POST("/products/:id/images", productHandler.AddProductImages)
-		api.POST("/products/:id/train", productHandler.TrainProduct)
 		api.POST("/products/:id/predict", productHandler.PredictProduct)
-		api.POST("/products/:id/pin", productHandler.PinProduct)
-		api.DELETE("/products/:id/unpin", productHandler.UnpinProduct)
 		api.GET("/products/pinned/:seller_id", productHandler.GetPinnedProducts)
-		api.DELETE("/products/unpin-all/:seller_id", productHandler.UnpinAllProducts)
 		api.GET("/training-status/:seller_id", productHandler.GetTrainingStatus)
-		api.POST("/train", productHandler.TrainAllSellers)
 	}
-}
\ No newline at end of file
+
+	seller := r.Group("/api", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.POST("/products", productHandler.CreateProduct)
+		seller.PUT("/products/:id", productHandler.UpdateProduct)
+		seller.PUT("/products/:id/stock", productHandler.UpdateStock)
+		seller.DELETE("/products/:id", productHandler.DeleteProduct)
+		seller.POST("/products/:id/images", productHandler.AddProductImages)
+		seller.POST("/products/:id/train", productHandler.TrainProduct)
+		seller.POST("/products/:id/pin", productHandler.PinProduct)
+		seller.DELETE("/products/:id/unpin", productHandler.UnpinProduct)
+		seller.DELETE("/products/unpin-all/:seller_id", productHandler.UnpinAllProducts)
+		seller.POST("/train", productHandler.TrainAllSellers)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRetrainRoutes(r *gin.Engine, retrainHandler *handlers.RetrainHandler, authService services.AuthService) {
+	seller := r.Group("/api/retrain", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.GET("/settings", retrainHandler.GetSettings)
+		seller.PUT("/settings", retrainHandler.UpdateSettings)
+	}
+}
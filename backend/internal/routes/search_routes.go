@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSearchRoutes(r *gin.Engine, searchHandler *handlers.SearchHandler, authService services.AuthService) {
+	// One visual search fans out to a prediction per seller, so it is
+	// rate limited to keep it from starving live detection of the ML
+	// service.
+	visualSearchLimit, err := strconv.Atoi(os.Getenv("VISUAL_SEARCH_RATE_LIMIT"))
+	if err != nil || visualSearchLimit <= 0 {
+		visualSearchLimit = 10
+	}
+
+	api := r.Group("/api")
+	{
+		api.POST("/search/visual",
+			middleware.OptionalAuth(authService),
+			middleware.RateLimit(visualSearchLimit),
+			searchHandler.VisualSearch,
+		)
+	}
+}
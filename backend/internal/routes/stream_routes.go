@@ -1,15 +1,24 @@
 package routes
 
 import (
+	"live-shopping-ai/backend/internal/domain/services"
 	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterStreamRoutes(r *gin.Engine, streamHandler *handlers.StreamHandler) {
+func RegisterStreamRoutes(r *gin.Engine, streamHandler *handlers.StreamHandler, authService services.AuthService) {
 	api := r.Group("/api/stream")
 	{
-		api.POST("/process-frame", streamHandler.ProcessStreamFrame)
 		api.POST("/predict", streamHandler.PredictFrame)
 	}
-}
\ No newline at end of file
+
+	// Frames sent here drive the seller's auto-pin policy, so only the
+	// seller may send them.
+	seller := r.Group("/api/stream", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.POST("/process-frame", streamHandler.ProcessStreamFrame)
+		seller.POST("/predict-batch", streamHandler.ProcessStreamFrames)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTrainingJobRoutes(r *gin.Engine, trainingJobHandler *handlers.TrainingJobHandler, authService services.AuthService) {
+	seller := r.Group("/api/training-jobs", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.GET("", trainingJobHandler.ListJobs)
+		seller.POST("", trainingJobHandler.CreateJob)
+		seller.GET("/:id", trainingJobHandler.GetJob)
+	}
+}
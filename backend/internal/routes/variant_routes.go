@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"live-shopping-ai/backend/internal/domain/services"
+	"live-shopping-ai/backend/internal/handlers"
+	"live-shopping-ai/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterVariantRoutes(r *gin.Engine, variantHandler *handlers.VariantHandler, authService services.AuthService) {
+	api := r.Group("/api")
+	{
+		api.GET("/products/:id/variants", variantHandler.GetVariants)
+	}
+
+	seller := r.Group("/api", middleware.RequireAuth(authService), middleware.RequireSeller())
+	{
+		seller.PUT("/products/:id/options", variantHandler.SetOptions)
+		seller.POST("/products/:id/variants", variantHandler.CreateVariant)
+		seller.PUT("/products/:id/variants/:variant_id", variantHandler.UpdateVariant)
+		seller.DELETE("/products/:id/variants/:variant_id", variantHandler.DeleteVariant)
+		seller.PUT("/products/:id/variants/:variant_id/stock", variantHandler.UpdateVariantStock)
+		seller.POST("/products/:id/variants/:variant_id/images", variantHandler.AddVariantImages)
+	}
+}
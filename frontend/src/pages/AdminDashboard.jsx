@@ -1,23 +1,87 @@
 import React, { useState, useEffect } from 'react';
-import { productAPI } from '../services/api';
+import { productAPI, trainingJobAPI, retrainAPI, authAPI, fetchAllPages } from '../services/api';
+import { WebSocketService } from '../services/websocket';
 import { LayoutDashboard, Package, Tv, FileText, TrendingUp, Settings, LogOut, Plus, Search, ChevronDown, Target, Edit, Trash2, Box } from 'lucide-react';
 
 const AdminDashboard = () => {
   const [products, setProducts] = useState([]);
   const [loading, setLoading] = useState(true);
   const [searchQuery, setSearchQuery] = useState('');
+  const [searchResults, setSearchResults] = useState(null);
   const [trainingStatus, setTrainingStatus] = useState({});
   const [isTraining, setIsTraining] = useState(false);
   const [showTrainingModal, setShowTrainingModal] = useState(false);
+  const [retrainSettings, setRetrainSettings] = useState(null);
 
   useEffect(() => {
     loadProducts();
   }, []);
 
+  // Training jobs report their progress over the seller's room
+  useEffect(() => {
+    const socket = new WebSocketService();
+    const handleJob = (message) => {
+      const job = message.data;
+      setTrainingStatus({ [job.seller_id]: job });
+
+      if (job.status === 'completed' || job.status === 'failed') {
+        setIsTraining(false);
+        alert(job.status === 'completed' ? 'Training completed!' : `Training failed: ${job.error}`);
+      } else {
+        setIsTraining(true);
+      }
+    };
+
+    authAPI.me()
+      .then(({ data }) => {
+        if (!data.seller) return;
+        const roomId = `seller-${data.seller.id}`;
+        socket.connect(`dashboard-${data.seller.id}-${Date.now()}`, roomId, 'dashboard');
+        socket.on('training_progress', handleJob);
+        socket.on('training_completed', handleJob);
+        socket.on('training_failed', handleJob);
+      })
+      .catch(() => {});
+
+    return () => socket.disconnect();
+  }, []);
+
+  useEffect(() => {
+    if (!showTrainingModal) return;
+    retrainAPI.getSettings()
+      .then(response => setRetrainSettings(response.data))
+      .catch(() => setRetrainSettings(null));
+  }, [showTrainingModal]);
+
+  const handleToggleAutoRetrain = async (enabled) => {
+    try {
+      const response = await retrainAPI.updateSettings(enabled);
+      setRetrainSettings(response.data);
+    } catch (error) {
+      alert('Failed to update auto-retrain setting');
+    }
+  };
+
+  useEffect(() => {
+    if (!searchQuery.trim()) {
+      setSearchResults(null);
+      return;
+    }
+
+    const timer = setTimeout(async () => {
+      try {
+        const response = await productAPI.search(searchQuery, { limit: 100 });
+        setSearchResults(response.data.data.map(hit => hit.product));
+      } catch (error) {
+        setSearchResults([]);
+      }
+    }, 250);
+    return () => clearTimeout(timer);
+  }, [searchQuery]);
+
   const loadProducts = async () => {
     try {
-      const response = await productAPI.getAll();
-      setProducts(response.data);
+      setProducts(await fetchAllPages(productAPI.getAll));
     } catch (error) {
     } finally {
       setLoading(false);
@@ -35,79 +99,42 @@ const AdminDashboard = () => {
     }
   };
 
+  // The job's own events may have arrived before the response that queued it
+  const showQueuedJob = (job) => {
+    setTrainingStatus(current => (
+      current[job.seller_id]?.id === job.id ? current : { [job.seller_id]: job }
+    ));
+  };
+
   const handleTrainModel = async (productId, sellerId) => {
     try {
-      await productAPI.train(productId);
-      alert(`Model training started for seller ${sellerId}`);
+      const response = await productAPI.train(productId);
+      alert(`Model training queued for seller ${sellerId}`);
+      setIsTraining(true);
+      showQueuedJob(response.data);
     } catch (error) {
-      alert('Failed to start training');
+      alert(error.response?.data?.error || 'Failed to start training');
     }
   };
 
-  const handleTrainAllProducts = async (sellerId, fineTune = false) => {
+  const handleTrainAllProducts = async (fineTune = false) => {
     const trainingType = fineTune ? 'fine-tune' : 'train';
-    if (!window.confirm(`${trainingType} ML model for Seller ${sellerId}? This will take a few minutes.`)) {
+    if (!window.confirm(`${trainingType} your ML model? This will take a few minutes.`)) {
       return;
     }
     
     setIsTraining(true);
     
     try {
-      const url = `${import.meta.env.VITE_API_URL}/api/train?seller_id=${sellerId}${fineTune ? '&fine_tune=true' : ''}`;
-      const response = await fetch(url, {
-        method: 'POST'
-      });
-      
-      if (!response.ok) {
-        throw new Error('Failed to start training');
-      }
-      
-      // Start polling backend for progress
-      pollTrainingProgress([sellerId]);
-      
+      const response = await trainingJobAPI.create({ fine_tune: fineTune });
+      showQueuedJob(response.data);
     } catch (error) {
-      alert('Failed to start training');
+      alert(error.response?.data?.error || 'Failed to start training');
       setIsTraining(false);
     }
   };
 
-  const pollTrainingProgress = (sellerIds) => {
-    const interval = setInterval(async () => {
-      try {
-        const statusPromises = sellerIds.map(async (sellerId) => {
-          const response = await fetch(`${import.meta.env.VITE_API_URL}/api/training-status/seller_${sellerId}`);
-          const status = await response.json();
-          return { sellerId, status };
-        });
-        
-        const statuses = await Promise.all(statusPromises);
-        const statusMap = {};
-        
-        statuses.forEach(({ sellerId, status }) => {
-          statusMap[sellerId] = status;
-        });
-        
-        setTrainingStatus(statusMap);
-        
-        const allComplete = statuses.every(({ status }) => 
-          status.status === 'completed' || status.status === 'error'
-        );
-        
-        if (allComplete) {
-          clearInterval(interval);
-          setIsTraining(false);
-          alert('Training completed!');
-        }
-      } catch (error) {
-      }
-    }, 2000);
-  };
-
-
-
-  const filteredProducts = products?.filter(p => 
-    p.name.toLowerCase().includes(searchQuery.toLowerCase())
-  ) || [];
+  const filteredProducts = searchResults ?? products ?? [];
 
   if (loading) {
     return <div className="flex justify-center items-center h-screen dark:bg-gray-900 dark:text-white">Loading...</div>;
@@ -216,12 +243,12 @@ const AdminDashboard = () => {
                     <div 
                       className={`h-2 rounded-full ${
                         status.status === 'completed' ? 'bg-green-500' : 
-                        status.status === 'error' ? 'bg-red-500' : 'bg-blue-500'
+                        status.status === 'failed' ? 'bg-red-500' : 'bg-blue-500'
                       }`}
                       style={{ width: `${status.progress || 0}%` }}
                     ></div>
                   </div>
-                  <span className="text-xs text-gray-600">{status.message}</span>
+                  <span className="text-xs text-gray-600">{status.error || status.message}</span>
                 </div>
               ))}
             </div>
@@ -358,26 +385,29 @@ const AdminDashboard = () => {
               Train ML Model
             </h3>
             <div className="space-y-4">
-              <div>
-                <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
-                  Select Seller
-                </label>
-                <select 
-                  id="seller-select"
-                  className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-white focus:ring-2 focus:ring-blue-500 outline-none"
-                >
-                  <option value="1">Seller 1</option>
-                  <option value="2">Seller 2</option>
-                  <option value="3">Seller 3</option>
-                </select>
-              </div>
+              {retrainSettings && (
+                <div>
+                  <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                    <input
+                      type="checkbox"
+                      checked={retrainSettings.auto_retrain}
+                      onChange={(e) => handleToggleAutoRetrain(e.target.checked)}
+                    />
+                    Retrain automatically when my catalogue changes
+                  </label>
+                  {retrainSettings.dirty_since && (
+                    <p className="mt-1 text-xs text-amber-600">
+                      Catalogue changed since the last training
+                    </p>
+                  )}
+                </div>
+              )}
               
               <div className="flex gap-3">
                 <button
                   onClick={() => {
-                    const sellerId = document.getElementById('seller-select').value;
                     setShowTrainingModal(false);
-                    handleTrainAllProducts(sellerId, false);
+                    handleTrainAllProducts(false);
                   }}
                   className="flex-1 px-4 py-2 bg-blue-500 text-white rounded-lg hover:bg-blue-600 transition-colors"
                 >
@@ -385,9 +415,8 @@ const AdminDashboard = () => {
                 </button>
                 <button
                   onClick={() => {
-                    const sellerId = document.getElementById('seller-select').value;
                     setShowTrainingModal(false);
-                    handleTrainAllProducts(sellerId, true);
+                    handleTrainAllProducts(true);
                   }}
                   className="flex-1 px-4 py-2 bg-green-500 text-white rounded-lg hover:bg-green-600 transition-colors"
                 >
@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useRef } from 'react';
 import { motion, AnimatePresence } from 'framer-motion';
-import { productAPI, streamAPI, pinAPI } from '../services/api';
+import { productAPI, streamAPI, pinAPI, fetchAllPages } from '../services/api';
 import websocketService from '../services/websocket';
 import webrtcService from '../services/webrtc';
 import { LayoutDashboard, Package, Tv, FileText, TrendingUp, Settings, LogOut, Video, Eye, Plus, Pin, Send, RotateCcw } from 'lucide-react';
@@ -14,6 +14,7 @@ const LiveStreamSeller = () => {
   const [pinnedProduct, setPinnedProduct] = useState(null);
   const [detectedProducts, setDetectedProducts] = useState([]);
   const [detectedObjects, setDetectedObjects] = useState([]);
+  const [pinSuggestion, setPinSuggestion] = useState(null);
   const [isProcessingFrame, setIsProcessingFrame] = useState(false);
   const [reactions, setReactions] = useState([]);
   const [currentCamera, setCurrentCamera] = useState('user'); // 'user' = front, 'environment' = back
@@ -30,6 +31,8 @@ const LiveStreamSeller = () => {
   const [newMessage, setNewMessage] = useState('');
   const videoRef = useRef(null);
   const frameProcessingRef = useRef(null);
+  // Set once the backend samples our video itself; local capture then stops
+  const serverSamplingRef = useRef(false);
 
   useEffect(() => {
     loadProducts();
@@ -55,8 +58,7 @@ const LiveStreamSeller = () => {
 
   const loadProducts = async () => {
     try {
-      const response = await productAPI.getBySellerId(sellerId);
-      setProducts(response.data);
+      setProducts(await fetchAllPages((params) => productAPI.getBySellerId(sellerId, params)));
     } catch (error) {
       // Error loading products
     }
@@ -173,6 +175,22 @@ const LiveStreamSeller = () => {
       websocketService.on('product_unpinned', (message) => {
         setPinnedProduct(null);
       });
+
+      // Products the auto-pin policy wants confirmed before pinning
+      websocketService.on('pin_suggestion', (message) => {
+        setPinSuggestion(message.data);
+      });
+
+      // Detections from frames we sent over the socket, or that the backend
+      // sampled from our WebRTC track
+      websocketService.on('detection_result', (message) => {
+        if (message.data?.source === 'server') {
+          serverSamplingRef.current = true;
+        }
+        const result = message.data?.result;
+        setDetectedProducts(result?.predictions || []);
+        setDetectedObjects(result?.detections || []);
+      });
     } catch (error) {
 
       alert('Failed to access camera. Please check permissions.');
@@ -230,7 +248,7 @@ const LiveStreamSeller = () => {
   const startFrameProcessing = () => {
     // Process frame every 5 seconds for CPU optimization
     frameProcessingRef.current = setInterval(async () => {
-      if (videoRef.current && !isProcessingFrame) {
+      if (videoRef.current && !isProcessingFrame && !serverSamplingRef.current) {
         await captureAndProcessFrame();
       }
     }, 5000);
@@ -251,46 +269,16 @@ const LiveStreamSeller = () => {
       
       // Convert to blob
       canvas.toBlob(async (blob) => {
-        if (blob) {
+        // Over the socket the result comes back as a detection_result
+        // message, and the server drops frames it cannot keep up with
+        const sentOverSocket = blob && websocketService.sendFrame(blob) !== null;
+        if (blob && !sentOverSocket) {
           try {
-            const response = await streamAPI.predictFrame(sellerId, blob);
+            // Pinning is decided by the backend's auto-pin policy
+            const response = await streamAPI.processFrame(blob);
             
             // Update detected products (recognized products)
-            if (response.data.predictions?.length > 0) {
-              setDetectedProducts(response.data.predictions);
-              
-              // Auto-pin high confidence products
-              const highConfidenceProducts = response.data.predictions.filter(
-                p => p.similarity_score >= 0.8
-              );
-              
-              if (highConfidenceProducts.length > 0) {
-                const bestProduct = highConfidenceProducts.reduce((prev, current) => 
-                  (prev.similarity_score > current.similarity_score) ? prev : current
-                );
-                
-                // Pin the best product
-                try {
-                  await pinAPI.pinProduct(bestProduct.product_id, parseInt(sellerId), bestProduct.similarity_score);
-                  
-                  // Send WebSocket message to notify viewers
-                  const pinMessage = {
-                    type: 'product_pinned',
-                    data: {
-                      product_id: bestProduct.product_id,
-                      product_name: bestProduct.product_name,
-                      price: bestProduct.price,
-                      similarity_score: bestProduct.similarity_score
-                    }
-                  };
-                  websocketService.send(pinMessage);
-                } catch (error) {
-                  console.error('❌ Failed to pin product:', error);
-                }
-              }
-            } else {
-              setDetectedProducts([]);
-            }
+            setDetectedProducts(response.data.predictions || []);
             
             // Update detected objects (all YOLO detections)
             if (response.data.detections?.length > 0) {
@@ -674,6 +662,34 @@ const LiveStreamSeller = () => {
                   )}
                 </div>
 
+                {pinSuggestion && (
+                  <div className="absolute bottom-4 left-4 right-4 flex items-center justify-between gap-3 bg-black/60 text-white px-4 py-3 rounded-lg backdrop-blur-sm">
+                    <div className="text-sm">
+                      <div className="font-semibold">Pin {pinSuggestion.product_name}?</div>
+                      <div className="text-xs opacity-80">
+                        ${pinSuggestion.price} · {Math.round(pinSuggestion.similarity_score * 100)}% match over {pinSuggestion.frames} frames
+                      </div>
+                    </div>
+                    <div className="flex gap-2">
+                      <button
+                        onClick={() => {
+                          pinProduct({ id: pinSuggestion.product_id, name: pinSuggestion.product_name, price: pinSuggestion.price });
+                          setPinSuggestion(null);
+                        }}
+                        className="bg-green-500 hover:bg-green-600 px-3 py-1.5 rounded text-sm font-medium"
+                      >
+                        Pin
+                      </button>
+                      <button
+                        onClick={() => setPinSuggestion(null)}
+                        className="bg-white/20 hover:bg-white/30 px-3 py-1.5 rounded text-sm"
+                      >
+                        Dismiss
+                      </button>
+                    </div>
+                  </div>
+                )}
+
                 {/* Floating Reactions */}
                 <div className="absolute inset-0 pointer-events-none overflow-hidden">
                   <AnimatePresence>
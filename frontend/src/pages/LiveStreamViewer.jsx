@@ -296,6 +296,14 @@ const LiveStreamViewer = () => {
       }
     });
 
+    websocketService.on('stock_updated', (message) => {
+      setPinnedProduct(prev => (
+        prev && prev.id === message.data.product_id
+          ? { ...prev, stock: message.data.stock, available: message.data.available }
+          : prev
+      ));
+    });
+
     websocketService.on('user_joined', (message) => {
       setViewerCount(prev => prev + 1);
     });
@@ -307,6 +315,7 @@ const LiveStreamViewer = () => {
     return () => {
       websocketService.off('product_pinned');
       websocketService.off('pin_product');
+      websocketService.off('stock_updated');
       websocketService.off('user_joined');
       websocketService.off('user_left');
     };
@@ -579,6 +588,11 @@ const LiveStreamViewer = () => {
                 <div className="flex-1">
                   <p className="text-white text-lg font-bold">{pinnedProduct.name || pinnedProduct.product_name}</p>
                   <p className="text-red-500 text-xl font-bold">${pinnedProduct.price}</p>
+                  {pinnedProduct.available !== undefined && (
+                    <p className="text-gray-300 text-sm">
+                      {pinnedProduct.available > 0 ? `${pinnedProduct.available} left` : 'Sold out'}
+                    </p>
+                  )}
                   {pinnedProduct.similarity_score && (
                     <p className="text-green-400 text-sm">
                       ✨ {Math.round(pinnedProduct.similarity_score * 100)}% Match
@@ -6,13 +6,44 @@ const api = axios.create({
   baseURL: `${API_BASE_URL}/api`,
 });
 
+api.interceptors.request.use((config) => {
+  const token = localStorage.getItem('access_token');
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
+const storeSession = (response) => {
+  localStorage.setItem('access_token', response.data.access_token);
+  localStorage.setItem('refresh_token', response.data.refresh_token);
+  return response;
+};
+
+// fetchAllPages follows next_cursor through a paginated list endpoint and
+// returns every item. list is called with the page params.
+export const fetchAllPages = async (list, params = {}) => {
+  const items = [];
+  let cursor;
+  do {
+    const { data } = await list({ ...params, limit: 100, ...(cursor ? { cursor } : {}) });
+    items.push(...data.data);
+    cursor = data.next_cursor;
+  } while (cursor);
+  return items;
+};
+
 export const productAPI = {
-  getAll: () => api.get('/products'),
+  // params: limit, cursor, sort, seller_id, min_price, max_price,
+  // created_after, has_images. Responses are { data, next_cursor, total }.
+  getAll: (params = {}) => api.get('/products', { params }),
   getById: (id) => api.get(`/products/${id}`),
-  getBySellerId: (sellerId) => api.get(`/products/seller/${sellerId}`),
+  // params: seller_id, livestream_id, pinned_live, limit.
+  search: (q, params = {}) => api.get('/products/search', { params: { q, ...params } }),
+  getBySellerId: (sellerId, params = {}) => api.get(`/products/seller/${sellerId}`, { params }),
   create: (product) => api.post('/products', product),
   createWithFile: (formData) => {
-    return axios.post(`${API_BASE_URL}/api/products`, formData, {
+    return api.post('/products', formData, {
       headers: {
         'Content-Type': 'multipart/form-data'
       }
@@ -20,7 +51,7 @@ export const productAPI = {
   },
   update: (id, product) => api.put(`/products/${id}`, product),
   addImages: (id, formData) => {
-    return axios.post(`${API_BASE_URL}/api/products/${id}/images`, formData, {
+    return api.post(`/products/${id}/images`, formData, {
       headers: {
         'Content-Type': 'multipart/form-data'
       }
@@ -41,10 +72,20 @@ export const streamAPI = {
   getAll: () => api.get('/streams'),
   create: (stream) => api.post('/streams', stream),
   updateStatus: (id, isActive) => api.put(`/streams/${id}/status`, { is_active: isActive }),
-  processFrame: (sellerId, frameFile) => {
+  // Runs detection and feeds the signed-in seller's auto-pin policy
+  processFrame: (frameFile) => {
     const formData = new FormData();
     formData.append('frame', frameFile);
-    return api.post(`/stream/process-frame?seller_id=${sellerId}`, formData, {
+    return api.post('/stream/process-frame', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' }
+    });
+  },
+  // Sends a burst of frames in one request; results come back in order
+  processFrames: (frameFiles, capturedAt = []) => {
+    const formData = new FormData();
+    frameFiles.forEach((frameFile) => formData.append('frames', frameFile));
+    capturedAt.forEach((time) => formData.append('captured_at', String(time)));
+    return api.post('/stream/predict-batch', formData, {
       headers: { 'Content-Type': 'multipart/form-data' }
     });
   },
@@ -57,8 +98,16 @@ export const streamAPI = {
   }
 };
 
+export const trainingJobAPI = {
+  // Trains the signed-in seller's catalogue; fails with 409 while another
+  // job is queued or running.
+  create: ({ fine_tune = false } = {}) => api.post('/training-jobs', { fine_tune }),
+  list: (params = {}) => api.get('/training-jobs', { params }),
+  get: (id) => api.get(`/training-jobs/${id}`)
+};
+
 export const mlAPI = {
-  trainModel: (sellerId) => api.post(`/products/train?seller_id=${sellerId}`),
+  trainModel: (fineTune = false) => api.post(`/train?fine_tune=${fineTune}`),
   predictProduct: (sellerId, imageFile) => {
     const formData = new FormData();
     formData.append('frame', imageFile);
@@ -68,11 +117,42 @@ export const mlAPI = {
   }
 };
 
+export const variantAPI = {
+  getAll: (productId) => api.get(`/products/${productId}/variants`),
+  setOptions: (productId, options) => api.put(`/products/${productId}/options`, { options }),
+  create: (productId, variant) => api.post(`/products/${productId}/variants`, variant),
+  update: (productId, variantId, variant) => api.put(`/products/${productId}/variants/${variantId}`, variant),
+  delete: (productId, variantId) => api.delete(`/products/${productId}/variants/${variantId}`),
+  updateStock: (productId, variantId, stock) => api.put(`/products/${productId}/variants/${variantId}/stock`, { stock }),
+  addImages: (productId, variantId, formData) => {
+    return api.post(`/products/${productId}/variants/${variantId}/images`, formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data'
+      }
+    });
+  }
+};
+
+export const searchAPI = {
+  // sellerIds defaults to every seller currently live.
+  visual: (imageFile, { sellerIds = [], minScore, limit } = {}) => {
+    const formData = new FormData();
+    formData.append('image', imageFile);
+    if (sellerIds.length > 0) formData.append('seller_ids', sellerIds.join(','));
+    if (minScore !== undefined) formData.append('min_score', minScore);
+    if (limit !== undefined) formData.append('limit', limit);
+    return api.post('/search/visual', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' }
+    });
+  }
+};
+
 export const pinAPI = {
-  pinProduct: (productId, sellerId, similarityScore) => {
+  pinProduct: (productId, sellerId, similarityScore, variantId = null) => {
     return api.post(`/products/${productId}/pin`, {
       seller_id: sellerId,
-      similarity_score: similarityScore
+      similarity_score: similarityScore,
+      variant_id: variantId
     });
   },
   unpinProduct: (productId, sellerId) => {
@@ -84,4 +164,62 @@ export const pinAPI = {
   getPinnedProducts: (sellerId) => api.get(`/products/pinned/${sellerId}`)
 };
 
-export default api;
\ No newline at end of file
+export const autoPinAPI = {
+  getSettings: () => api.get('/autopin/settings'),
+  // mode is one of off, suggest or auto
+  updateSettings: (settings) => api.put('/autopin/settings', settings)
+};
+
+export const retrainAPI = {
+  // Also reports whether the catalogue has changes the model does not cover
+  getSettings: () => api.get('/retrain/settings'),
+  updateSettings: (autoRetrain) => api.put('/retrain/settings', { auto_retrain: autoRetrain })
+};
+
+export const detectionSettingsAPI = {
+  getSettings: () => api.get('/detection/settings'),
+  // roi is { x, y, width, height } in fractions of the frame, or null
+  updateSettings: (settings) => api.put('/detection/settings', settings),
+  resetSettings: () => api.delete('/detection/settings')
+};
+
+export const authAPI = {
+  register: (account) => api.post('/auth/register', account).then(storeSession),
+  login: (email, password) => api.post('/auth/login', { email, password }).then(storeSession),
+  refresh: () => api.post('/auth/refresh', {
+    refresh_token: localStorage.getItem('refresh_token')
+  }).then(storeSession),
+  logout: () => api.post('/auth/logout').finally(() => {
+    localStorage.removeItem('access_token');
+    localStorage.removeItem('refresh_token');
+  }),
+  me: () => api.get('/auth/me'),
+  getStreamToken: (role) => api.post('/auth/stream-token', { role })
+};
+
+export default api;
+export const cartAPI = {
+  get: () => api.get('/cart'),
+  addItem: (productId, liveStreamId, quantity = 1, variantId = null) => api.post('/cart/items', {
+    product_id: productId,
+    variant_id: variantId,
+    livestream_id: liveStreamId,
+    quantity
+  }),
+  updateItem: (itemId, quantity) => api.put(`/cart/items/${itemId}`, { quantity }),
+  removeItem: (itemId) => api.delete(`/cart/items/${itemId}`),
+  checkout: () => api.post('/cart/checkout')
+};
+
+export const orderAPI = {
+  list: () => api.get('/orders'),
+  get: (orderId) => api.get(`/orders/${orderId}`),
+  cancel: (orderId, note) => api.post(`/orders/${orderId}/cancel`, { note }),
+  listSellerOrders: (status) => api.get('/seller/orders', { params: status ? { status } : {} }),
+  transition: (orderId, status, note) => api.post(`/orders/${orderId}/transitions`, { status, note })
+};
+
+export const paymentAPI = {
+  payOrder: (orderId) => api.post(`/orders/${orderId}/pay`),
+  refundOrder: (orderId) => api.post(`/orders/${orderId}/refund`)
+};
@@ -1,6 +1,13 @@
+import { authAPI } from './api';
+
 const WS_URL = import.meta.env.VITE_WS_URL || 'ws://localhost:8080';
+const PROTOCOL_VERSION = 3;
+// Protocol version from which detection frames can be sent as binary messages
+const FRAME_PROTOCOL_VERSION = 3;
+const FRAME_MESSAGE_KIND = 0x01;
+const FRAME_HEADER_SIZE = 13;
 
-class WebSocketService {
+export class WebSocketService {
   constructor() {
     this.socket = null;
     this.listeners = new Map();
@@ -8,6 +15,8 @@ class WebSocketService {
     this.maxReconnectAttempts = 5;
     this.reconnectDelay = 1000;
     this.isConnecting = false;
+    this.protocolVersion = 0;
+    this.frameSequence = 0;
     this.connectionCallbacks = {
       onConnected: null,
       onDisconnected: null,
@@ -15,7 +24,8 @@ class WebSocketService {
     };
   }
 
-  connect(clientId, roomId) {
+  // role defaults to publisher for seller client IDs and viewer otherwise
+  connect(clientId, roomId, role) {
     if (!clientId || !roomId) {
       return;
     }
@@ -27,14 +37,26 @@ class WebSocketService {
     this.isConnecting = true;
     this.clientId = clientId;
     this.roomId = roomId;
+    this.role = role || (clientId.includes('seller') ? 'publisher' : 'viewer');
 
     // Clean up existing connection
     if (this.socket) {
       this.disconnect();
     }
 
+    const role = this.role;
+
+    authAPI.getStreamToken(role)
+      .then(({ data }) => this.open(clientId, roomId, role, data.token))
+      .catch((error) => {
+        this.isConnecting = false;
+        this.emit('error', error);
+      });
+  }
+
+  open(clientId, roomId, role, token) {
     try {
-      const url = `${WS_URL}/ws/livestream`;
+      const url = `${WS_URL}/ws/livestream?token=${encodeURIComponent(token)}`;
       
       this.socket = new WebSocket(url);
       
@@ -48,7 +70,8 @@ class WebSocketService {
           room: roomId,
           data: {
             client_id: clientId,
-            role: clientId.includes('seller') ? 'publisher' : 'viewer'
+            role,
+            protocol_version: PROTOCOL_VERSION
           }
         });
         
@@ -62,6 +85,9 @@ class WebSocketService {
       this.socket.onmessage = (event) => {
         try {
           const message = JSON.parse(event.data);
+          if (message.type === 'joined') {
+            this.protocolVersion = message.data?.protocol_version || 0;
+          }
           this.emit(message.type, message);
         } catch (error) {
         }
@@ -69,6 +95,7 @@ class WebSocketService {
 
       this.socket.onclose = (event) => {
         this.isConnecting = false;
+        this.protocolVersion = 0;
         this.emit('disconnected', { code: event.code, reason: event.reason });
         
         if (this.connectionCallbacks.onDisconnected) {
@@ -103,7 +130,7 @@ class WebSocketService {
     
     setTimeout(() => {
       if (this.reconnectAttempts <= this.maxReconnectAttempts) {
-        this.connect(clientId, roomId);
+        this.connect(clientId, roomId, this.role);
       } else {
         this.emit('reconnection_failed');
       }
@@ -120,6 +147,7 @@ class WebSocketService {
       this.socket = null;
     }
     this.isConnecting = false;
+    this.protocolVersion = 0;
     this.reconnectAttempts = 0;
     this.listeners.clear();
   }
@@ -214,6 +242,33 @@ class WebSocketService {
     });
   }
 
+  // Whether the server takes detection frames on this socket
+  canSendFrames() {
+    return this.protocolVersion >= FRAME_PROTOCOL_VERSION
+      && this.socket?.readyState === WebSocket.OPEN;
+  }
+
+  // Sends a JPEG frame for detection; the result arrives as a
+  // detection_result message carrying the returned sequence number
+  sendFrame(jpegBlob) {
+    if (!this.canSendFrames()) {
+      return null;
+    }
+
+    this.frameSequence = (this.frameSequence + 1) >>> 0;
+    const header = new DataView(new ArrayBuffer(FRAME_HEADER_SIZE));
+    header.setUint8(0, FRAME_MESSAGE_KIND);
+    header.setUint32(1, this.frameSequence);
+    header.setBigInt64(5, BigInt(Date.now()));
+
+    try {
+      this.socket.send(new Blob([header.buffer, jpegBlob]));
+      return this.frameSequence;
+    } catch (error) {
+      return null;
+    }
+  }
+
   // Chat method
   sendChat(message, username) {
     return this.send({
@@ -1,6 +1,8 @@
 from fastapi import FastAPI, File, UploadFile, HTTPException, BackgroundTasks, Form
 from fastapi.responses import JSONResponse
 import os
+import json
+from typing import List, Optional
 from models.yolo_detector import YOLODetector
 from models.clip_extractor import CLIPExtractor
 from models.faiss_index import FAISSIndex
@@ -104,9 +106,22 @@ async def run_fine_tuning_pipeline(seller_key: str):
         logger.error(f"Fine-tuning error for {seller_key}: {e}")
         training_status[seller_key] = {"status": "error", "progress": 0, "message": str(e)}
 
+def parse_detection_settings(settings: Optional[str]):
+    """Decode the seller's detection settings sent by the backend as JSON"""
+    if not settings:
+        return None
+    try:
+        parsed = json.loads(settings)
+    except ValueError as e:
+        raise HTTPException(status_code=422, detail=f"Invalid settings: {e}")
+    if not isinstance(parsed, dict):
+        raise HTTPException(status_code=422, detail="Invalid settings: expected an object")
+    return parsed
+
 @app.post("/predict")
-async def predict_products(seller_id: str = Form(...), file: UploadFile = File(...)):
+async def predict_products(seller_id: str = Form(...), file: UploadFile = File(...), settings: Optional[str] = Form(None)):
     """Predict products in live stream frame - PRODUCTION ENDPOINT"""
+    detection_settings = parse_detection_settings(settings)
     try:
         print(f"🔍 PREDICT REQUEST: seller_id={seller_id}, file={file.filename}")
         logger.info(f"🔍 PREDICT REQUEST: seller_id={seller_id}, file={file.filename}")
@@ -128,7 +143,7 @@ async def predict_products(seller_id: str = Form(...), file: UploadFile = File(.
         image_data = await file.read()
         logger.info(f"📷 Image size: {len(image_data)} bytes")
         
-        result = await trainer_service.detect_products(seller_key, image_data)
+        result = await trainer_service.detect_products(seller_key, image_data, detection_settings)
         logger.info(f"🎯 Detection result: {result}")
         
         return {
@@ -147,35 +162,58 @@ async def predict_products(seller_id: str = Form(...), file: UploadFile = File(.
             'error': str(e)
         }
 
+@app.post("/predict-batch")
+async def predict_products_batch(seller_id: str = Form(...), files: List[UploadFile] = File(...), settings: Optional[str] = Form(None)):
+    """Predict products in several frames of a live stream in one request.
+
+    Results are in the order of the files and each has the shape /predict
+    returns, so one bad frame reports its own error without failing the rest.
+    """
+    logger.info(f"🔍 PREDICT BATCH REQUEST: seller_id={seller_id}, frames={len(files)}")
+    detection_settings = parse_detection_settings(settings)
+
+    seller_key = f"seller_{seller_id}"
+    if seller_key not in faiss_index.seller_indices:
+        logger.warning(f"❌ No model found for {seller_key}")
+        return {
+            'results': [{
+                'predictions': [],
+                'total_detections': 0,
+                'message': f'No trained model found for seller {seller_id}. Please train first.'
+            } for _ in files]
+        }
+
+    results = []
+    for index, file in enumerate(files):
+        try:
+            image_data = await file.read()
+            result = await trainer_service.detect_products(seller_key, image_data, detection_settings)
+            results.append({
+                'predictions': result.get('predictions', []),
+                'detections': result.get('detections', []),
+                'total_detections': len(result.get('detections', [])),
+                'total_products': len(result.get('predictions', []))
+            })
+        except Exception as e:
+            logger.error(f"❌ Prediction error in frame {index}: {e}")
+            results.append({
+                'predictions': [],
+                'total_detections': 0,
+                'error': str(e)
+            })
+    return {'results': results}
+
 @app.post("/detect-live")
 async def detect_products_live(seller_id: str, file: UploadFile = File(...)):
-    """Detect products in live stream frame with auto-pin to cart"""
+    """Detect products in live stream frame. Pinning is decided by the backend's auto-pin policy."""
     try:
         image_data = await file.read()
         result = await trainer_service.detect_products(seller_id, image_data)
         
-        # Auto-pin high confidence products to cart
-        backend_url = os.getenv('BACKEND_URL', 'http://backend:8080')
-        pinned_products = []
-        
-        for prediction in result.get('predictions', []):
-            if prediction['similarity_score'] > 0.8:  # High confidence threshold
-                try:
-                    import requests
-                    # Send to backend API to pin product
-                    pin_response = requests.post(
-                        f"{backend_url}/api/products/{prediction['product_id']}/pin",
-                        json={'seller_id': seller_id, 'similarity_score': prediction['similarity_score']}
-                    )
-                    if pin_response.status_code == 200:
-                        pinned_products.append(prediction['product_id'])
-                except Exception as e:
-                    logger.error(f"Failed to pin product {prediction['product_id']}: {e}")
-        
         return {
             'predictions': result.get('predictions', []),
-            'pinned_products': pinned_products,
-            'message': f"Detected {len(result.get('predictions', []))} objects, pinned {len(pinned_products)} products"
+            'detections': result.get('detections', []),
+            'message': f"Detected {len(result.get('predictions', []))} objects"
         }
     except Exception as e:
         raise HTTPException(status_code=500, detail=str(e))
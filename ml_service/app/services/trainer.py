@@ -17,15 +17,22 @@ class TrainerService:
         self.config = config
     
     def fetch_products_from_api(self):
-        """Fetch all products from backend API"""
+        """Fetch all products from backend API, following its pagination"""
         backend_url = os.getenv('BACKEND_URL', 'http://backend:8080')
+        products = []
+        params = {'limit': 100}
         try:
-            response = requests.get(f"{backend_url}/api/products")
-            if response.status_code == 200:
-                return response.json()
-            else:
-                logger.error(f"Failed to fetch products: {response.status_code}")
-                return []
+            while True:
+                response = requests.get(f"{backend_url}/api/products", params=params)
+                if response.status_code != 200:
+                    logger.error(f"Failed to fetch products: {response.status_code}")
+                    return []
+                page = response.json()
+                products.extend(page.get('data') or [])
+                cursor = page.get('next_cursor')
+                if not cursor:
+                    return products
+                params['cursor'] = cursor
         except Exception as e:
             logger.error(f"Error fetching products: {e}")
             return []
@@ -268,9 +275,21 @@ class TrainerService:
             "status": "success"
         }
     
-    async def detect_products(self, seller_id: str, image_data: bytes):
-        """Detect products in image"""
-        logger.info(f"🔍 DETECT_PRODUCTS: seller_id={seller_id}")
+    def detection_settings(self, settings: dict = None):
+        """Fill in the defaults for detection settings the request left out"""
+        settings = settings or {}
+        ignored_classes = settings.get('ignored_classes')
+        return {
+            'similarity_threshold': float(settings.get('similarity_threshold', self.config.SIMILARITY_THRESHOLD)),
+            'min_confidence': float(settings.get('min_confidence', self.config.YOLO_CONF_THRESHOLD)),
+            'ignored_classes': set(self.config.IGNORED_CLASSES if ignored_classes is None else ignored_classes),
+            'roi': settings.get('roi'),
+        }
+
+    async def detect_products(self, seller_id: str, image_data: bytes, settings: dict = None):
+        """Detect products in image using the seller's detection settings"""
+        settings = self.detection_settings(settings)
+        logger.info(f"🔍 DETECT_PRODUCTS: seller_id={seller_id}, settings={settings}")
         logger.info(f"📊 Available indices: {list(self.faiss_index.seller_indices.keys())}")
         
         if seller_id not in self.faiss_index.seller_indices:
@@ -288,22 +307,39 @@ class TrainerService:
         logger.info(f"🤖 Running YOLO11 detection")
         detections = self.yolo_detector.detect(
             image,
-            conf_threshold=self.config.YOLO_CONF_THRESHOLD,
+            conf_threshold=settings['min_confidence'],
             iou_threshold=self.config.YOLO_IOU_THRESHOLD
         )
         logger.info(f"🎯 YOLO11 found {len(detections)} detections")
+
+        # Region of interest in pixels; it is sent in fractions of the frame
+        roi = None
+        if settings['roi']:
+            width, height = image.size
+            roi = (
+                settings['roi']['x'] * width,
+                settings['roi']['y'] * height,
+                (settings['roi']['x'] + settings['roi']['width']) * width,
+                (settings['roi']['y'] + settings['roi']['height']) * height,
+            )
         
         predictions = []
         all_detections = []
         
         # Process YOLO detections (now returns structured data)
         for detection in detections:
-            # Skip person class for product detection
-            if detection.get('class_id') == 0:
+            # Skip classes the seller does not sell, such as themselves
+            if detection.get('class') in settings['ignored_classes']:
                 continue
                 
             bbox = detection['bbox']
             x1, y1, x2, y2 = bbox
+
+            # Skip objects centred outside the region of interest
+            if roi:
+                center_x, center_y = (x1 + x2) / 2, (y1 + y2) / 2
+                if not (roi[0] <= center_x <= roi[2] and roi[1] <= center_y <= roi[3]):
+                    continue
             
             # Add to all detections for object tracking
             all_detections.append(detection)
@@ -318,7 +354,7 @@ class TrainerService:
             results = self.faiss_index.search(seller_id, embedding, k=1)
             logger.info(f"📊 FAISS results: {results}")
             
-            if results and results[0]["similarity_score"] > 0.7:  # Threshold for product match
+            if results and results[0]["similarity_score"] > settings['similarity_threshold']:
                 result = results[0]
                 # Extract numeric product ID from product_X format
                 product_id_str = result["product_id"].replace("product_", "")
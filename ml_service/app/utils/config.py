@@ -21,4 +21,8 @@ class Config:
         # YOLO11 optimized settings
         self.YOLO_CONF_THRESHOLD = 0.4
         self.YOLO_IOU_THRESHOLD = 0.5
-        self.MIN_OBJECT_SIZE = 30
\ No newline at end of file
+        self.MIN_OBJECT_SIZE = 30
+
+        # Prediction defaults; the backend sends each seller's own with every request
+        self.SIMILARITY_THRESHOLD = 0.7
+        self.IGNORED_CLASSES = ['person']
\ No newline at end of file
@@ -217,16 +217,23 @@ def save_faiss_index(index, metadata, seller_id):
         return False
 
 def fetch_products_from_api():
-    """Fetch all products from backend API"""
+    """Fetch all products from backend API, following its pagination"""
     backend_url = os.getenv('BACKEND_URL', 'http://100.64.5.96:7080')
     print(f"Using backend URL: {backend_url}")
+    products = []
+    params = {'limit': 100}
     try:
-        response = requests.get(f"{backend_url}/api/products")
-        if response.status_code == 200:
-            return response.json()
-        else:
-            print(f"Failed to fetch products: {response.status_code}")
-            return []
+        while True:
+            response = requests.get(f"{backend_url}/api/products", params=params)
+            if response.status_code != 200:
+                print(f"Failed to fetch products: {response.status_code}")
+                return []
+            page = response.json()
+            products.extend(page.get('data') or [])
+            cursor = page.get('next_cursor')
+            if not cursor:
+                return products
+            params['cursor'] = cursor
     except Exception as e:
         print(f"Error fetching products: {e}")
         return []